@@ -1,8 +1,6 @@
 package gogql
 
 import (
-	"reflect"
-
 	"github.com/cipriantarta/gogql/pkg/builder"
 	"github.com/graphql-go/graphql"
 )
@@ -22,7 +20,12 @@ type InputObjects map[string]graphql.Input
 // OutputObjects - graphql map for output types
 type OutputObjects map[string]graphql.Output
 
+// SchemaError - every problem found while building a schema
+type SchemaError = builder.SchemaError
+
 //New builds a new graphl Schema
+//
+// Deprecated: use NewSchema with options instead
 func New(
 	query interface{},
 	mutation interface{},
@@ -34,60 +37,49 @@ func New(
 	mutationTypes InputObjects,
 	paginationLimit int) (*graphql.Schema, error) {
 
-	b := builder.New()
-	b.PaginationLimit = paginationLimit
-	for k, v := range scalars {
-		b.Scalar(k, v)
-	}
-	for k, v := range queryTypes {
-		b.Object(k, v)
-	}
-	for k, v := range enums {
-		b.Enum(k, v)
-	}
+	return NewSchema(query,
+		WithMutation(mutation),
+		WithSubscription(subscription),
+		WithScalars(scalars),
+		WithEnums(enums),
+		WithInterfaces(interfaces),
+		WithObjects(queryTypes),
+		WithInputObjects(mutationTypes),
+		WithPaginationLimit(paginationLimit),
+	)
+}
 
-	qf, err := b.QueryFields(reflect.ValueOf(query), reflect.Value{})
-	if err != nil {
-		return nil, err
+// NewSchema builds a new graphql Schema from the query root and the given options.
+// Every problem found in the Go types is returned at once as a *SchemaError.
+// Each call uses its own builder, so schemas may be built concurrently
+func NewSchema(query interface{}, opts ...Option) (*graphql.Schema, error) {
+	c := &config{builder: builder.New()}
+	for _, opt := range opts {
+		opt(c)
 	}
-
-	var mutationObj *graphql.Object
-	if mutation != nil {
-		mf, err := b.QueryFields(reflect.ValueOf(mutation), reflect.Value{})
-		if err != nil {
-			return nil, err
-		}
-		mutationObj = graphql.NewObject(
-			graphql.ObjectConfig{
-				Name:   "Mutation",
-				Fields: mf,
-			})
+	var schema *graphql.Schema
+	var err error
+	if c.sdl != "" {
+		schema, err = c.builder.Bind(c.sdl, query, c.mutation, c.subscription)
+	} else {
+		schema, err = c.builder.Schema(query, c.mutation, c.subscription)
 	}
-
-	var subscriptionObj *graphql.Object
-	if subscription != nil {
-		sf, err := b.QueryFields(reflect.ValueOf(subscription), reflect.Value{})
-		if err != nil {
-			return nil, err
-		}
-		subscriptionObj = graphql.NewObject(
-			graphql.ObjectConfig{
-				Name:   "Subscription",
-				Fields: sf,
-			})
+	if err == nil && c.printTo != nil {
+		*c.printTo, err = c.builder.SDL()
 	}
+	return schema, err
+}
 
-	s, err := graphql.NewSchema(graphql.SchemaConfig{
-		Query: graphql.NewObject(
-			graphql.ObjectConfig{
-				Name:   "Query",
-				Fields: qf,
-			}),
-		Mutation:     mutationObj,
-		Subscription: subscriptionObj,
-	})
-	if err != nil {
-		return nil, err
-	}
-	return &s, nil
+// PrintSchema - prints a schema in the schema definition language, with the
+// fields sorted by name. Use WithPrintedSchema or builder.Builder.SDL to keep
+// the declaration order of the Go struct fields
+func PrintSchema(schema *graphql.Schema) string {
+	return builder.PrintSchema(schema)
+}
+
+// Do - executes a request like graphql.Do, keeping the arguments and input
+// fields set to null, as literals or variables, for the types.FieldMask of
+// resolvers, which graphql-go drops
+func Do(p graphql.Params) *graphql.Result {
+	return builder.Do(p)
 }
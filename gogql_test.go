@@ -1,16 +1,31 @@
 package gogql_test
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+	"unicode"
 
 	"github.com/cipriantarta/gogql"
+	"github.com/cipriantarta/gogql/pkg/builder"
+	"github.com/cipriantarta/gogql/pkg/scalars"
 	"github.com/cipriantarta/gogql/pkg/types"
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
 	"github.com/graphql-go/graphql/testutil"
 )
 
 type ID int
 type M = map[string]interface{}
+
 type VehicleAttributes struct {
 	Color string
 	Power int
@@ -187,3 +202,2142 @@ func TestQueryConnection(t *testing.T) {
 		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
 	}
 }
+
+func TestNewSchemaOptions(t *testing.T) {
+	named := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "INamed",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	attributes := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AttributesInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"color": &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"power": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+	s, err := gogql.NewSchema(&Query{},
+		gogql.WithMutation(&Mutation{}),
+		gogql.WithInterfaces(gogql.Interfaces{"INamed": named}),
+		gogql.WithInputObjects(gogql.InputObjects{"VehicleAttributes": attributes}),
+		gogql.WithPaginationLimit(10),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{
+                named: __type(name: "INamed") { name }
+                attributes: __type(name: "AttributesInput") { name }
+        }`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{
+		"named":      M{"name": "INamed"},
+		"attributes": M{"name": "AttributesInput"},
+	}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	rs := `mutation {createVehicle(make: "VW", attributes: {color: "red"}) {attributes {color}}}`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: rs})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+}
+
+type BadQuery struct {
+	Hello string
+	Count int
+	Users []*User `relay:"method=String"`
+}
+
+func (q *BadQuery) ResolveHello() string {
+	return "world"
+}
+
+func (q *BadQuery) ResolveCount(p graphql.ResolveParams, limit int) (int, error) {
+	return 0, nil
+}
+
+func (q *BadQuery) ResolveUsers(p graphql.ResolveParams, pageArgs *types.PageArguments) (*User, error) {
+	return nil, nil
+}
+
+func TestSchemaError(t *testing.T) {
+	_, err := gogql.NewSchema(&BadQuery{})
+	schemaErr, ok := err.(*gogql.SchemaError)
+	if !ok {
+		t.Fatalf("expected a *SchemaError, got %v", err)
+	}
+	expected := []string{
+		"github.com/cipriantarta/gogql_test.BadQuery.ResolveHello: expected two output params, got 1",
+		"github.com/cipriantarta/gogql_test.BadQuery.ResolveCount: second argument must be a struct",
+		"github.com/cipriantarta/gogql_test.BadQuery.ResolveUsers: first output parameter must be a slice when using relay",
+	}
+	if len(schemaErr.Errors) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), err)
+	}
+	for i, e := range expected {
+		if schemaErr.Errors[i].Error() != e {
+			t.Errorf("expected %q, got %q", e, schemaErr.Errors[i].Error())
+		}
+	}
+}
+
+type Book struct {
+	Title  string `graphql:"required,description=\"Title of the book\""`
+	Author string
+	ISBN   string `graphql:"alias=isbn"`
+}
+
+type BookSearch struct {
+	Title string `graphql:"description=\"Part of the title\""`
+	Limit int
+}
+
+type Library struct {
+	Books []*Book
+}
+
+func (l *Library) ResolveBooks(p graphql.ResolveParams, search *BookSearch) ([]*Book, error) {
+	return nil, nil
+}
+
+func TestSDL(t *testing.T) {
+	b := builder.New()
+	s, err := b.Schema(&Library{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := `type Book {
+  """Title of the book"""
+  title: String!
+  author: String
+  isbn: String
+}
+
+type Query {
+  books(
+    """Part of the title"""
+    title: String
+    limit: Int
+  ): [Book]
+}
+`
+	if sdl != e {
+		t.Fatalf("Bad SDL, expected:\n%s\ngot:\n%s", e, sdl)
+	}
+
+	e = `type Book {
+  author: String
+  isbn: String
+  """Title of the book"""
+  title: String!
+}
+
+type Query {
+  books(
+    limit: Int
+    """Part of the title"""
+    title: String
+  ): [Book]
+}
+`
+	if sdl := gogql.PrintSchema(s); sdl != e {
+		t.Fatalf("Bad SDL, expected:\n%s\ngot:\n%s", e, sdl)
+	}
+
+	var printed string
+	if _, err := gogql.NewSchema(&Library{}, gogql.WithPrintedSchema(&printed)); err != nil {
+		t.Fatal(err)
+	}
+	if printed != sdl {
+		t.Fatalf("Bad SDL, expected:\n%s\ngot:\n%s", sdl, printed)
+	}
+
+	b = builder.New()
+	if _, err := b.Schema(&Catalog{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sdl, err = b.SDL(); err != nil {
+		t.Fatal(err)
+	}
+	e = `input PeriodInput {
+  """First year of publication"""
+  from: Int
+  to: Int
+}
+`
+	if !strings.Contains(sdl, e) {
+		t.Fatalf("Bad SDL, expected:\n%s\nin:\n%s", e, sdl)
+	}
+}
+
+type Period struct {
+	From int `graphql:"description=\"First year of publication\""`
+	To   int
+}
+
+type CatalogSearch struct {
+	Published *Period
+}
+
+type Catalog struct {
+	Books []*Book
+}
+
+func (c *Catalog) ResolveBooks(p graphql.ResolveParams, search *CatalogSearch) ([]*Book, error) {
+	return nil, nil
+}
+
+type Greeting struct {
+	Text string `graphql:"description='Say \\'hi\\' or \"hello\"'"`
+}
+
+func TestSDLDescriptionQuotes(t *testing.T) {
+	b := builder.New()
+	if _, err := b.Schema(&Greeting{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: sdl})
+	if err != nil {
+		t.Fatalf("SDL does not parse: %s\n%s", err, sdl)
+	}
+	field := doc.Definitions[0].(*ast.ObjectDefinition).Fields[0]
+	if e := `Say 'hi' or "hello"`; field.Description == nil || field.Description.Value != e {
+		t.Fatalf("Bad description, expected %s, got %v\n%s", e, field.Description, sdl)
+	}
+}
+
+func TestSDLBinding(t *testing.T) {
+	sdl := `
+type Book {
+  title: String!
+  author: String
+  isbn: String
+}
+
+type Query {
+  books(title: String, limit: Int): [Book]
+}
+`
+	if _, err := gogql.NewSchema(&Library{}, gogql.WithSDL(sdl)); err != nil {
+		t.Fatal(err)
+	}
+
+	sdl = `
+type Book {
+  title: String
+  author: String
+  pages: Int
+}
+
+type Query {
+  books(title: String, offset: Int): [Book]
+}
+`
+	_, err := gogql.NewSchema(&Library{}, gogql.WithSDL(sdl))
+	schemaErr, ok := err.(*gogql.SchemaError)
+	if !ok {
+		t.Fatalf("expected a *SchemaError, got %v", err)
+	}
+	expected := []string{
+		"github.com/cipriantarta/gogql_test.Book.Title: type is String in SDL but String! in Go",
+		"github.com/cipriantarta/gogql_test.Book.pages: field pages is declared in SDL but has neither a Go field nor a resolver ResolvePages",
+		"github.com/cipriantarta/gogql_test.Book.ISBN: field isbn exists in Go but is not declared in SDL",
+		"github.com/cipriantarta/gogql_test.Library.Books: argument offset is declared in SDL but missing in Go",
+		"github.com/cipriantarta/gogql_test.Library.Books: argument limit exists in Go but is not declared in SDL",
+	}
+	if len(schemaErr.Errors) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), err)
+	}
+	for i, e := range expected {
+		if schemaErr.Errors[i].Error() != e {
+			t.Errorf("expected %q, got %q", e, schemaErr.Errors[i].Error())
+		}
+	}
+}
+
+type Order int
+
+const (
+	Asc Order = iota
+	Desc
+	Random
+)
+
+func (o Order) String() string {
+	return [...]string{"ASC", "DESC", "RANDOM"}[o]
+}
+
+func (o Order) EnumValues() []Order {
+	return []Order{Asc, Desc, Random}
+}
+
+func (o Order) EnumDescription() string {
+	if o == Asc {
+		return "Ascending order"
+	}
+	return ""
+}
+
+func (o Order) EnumDeprecationReason() string {
+	if o == Random {
+		return "Use ASC or DESC"
+	}
+	return ""
+}
+
+type SortArgs struct {
+	Order Order
+}
+
+type SortQuery struct {
+	Order Order
+}
+
+func (q *SortQuery) ResolveOrder(p graphql.ResolveParams, args *SortArgs) (Order, error) {
+	return args.Order, nil
+}
+
+func TestEnum(t *testing.T) {
+	b := builder.New()
+	s, err := b.Schema(&SortQuery{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{order(order: DESC)}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"order": "DESC"}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `enum Order {
+  """Ascending order"""
+  ASC
+  DESC
+  RANDOM @deprecated(reason: "Use ASC or DESC")
+}
+
+type Query {
+  order(order: Order): Order
+}
+`
+	if sdl != expected {
+		t.Fatalf("Bad SDL, expected:\n%s\ngot:\n%s", expected, sdl)
+	}
+}
+
+type SearchResult interface {
+	isSearchResult()
+}
+
+type Photo struct {
+	Width  int
+	Height int
+}
+
+func (*Photo) isSearchResult() {}
+
+type Person struct {
+	Name string
+}
+
+func (*Person) isSearchResult() {}
+
+type SearchQuery struct {
+	Search []SearchResult
+}
+
+func (q *SearchQuery) ResolveSearch(p graphql.ResolveParams) ([]SearchResult, error) {
+	return []SearchResult{&Photo{Width: 640, Height: 480}, &Person{Name: "Ann"}}, nil
+}
+
+func TestUnion(t *testing.T) {
+	s, err := gogql.NewSchema(&SearchQuery{},
+		gogql.WithUnion((*SearchResult)(nil), &Photo{}, &Person{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{
+                search {
+                        __typename
+                        ... on Photo { width height }
+                        ... on Person { name }
+                }
+        }`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{
+		"search": []interface{}{
+			M{"__typename": "Photo", "width": 640, "height": 480},
+			M{"__typename": "Person", "name": "Ann"},
+		},
+	}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+	if sdl := gogql.PrintSchema(s); !strings.Contains(sdl, "union SearchResult = Photo | Person") {
+		t.Fatalf("expected the union in the SDL, got:\n%s", sdl)
+	}
+}
+
+type Node interface {
+	NodeID() string
+}
+
+type Entity struct {
+	ID string `graphql:"required"`
+}
+
+func (e *Entity) NodeID() string {
+	return e.ID
+}
+
+type Article struct {
+	Entity `graphql:"interface"`
+	Title  string
+}
+
+type Author struct {
+	Entity `graphql:"interface"`
+	Name   string
+}
+
+type FeedQuery struct {
+	Feed []Node
+}
+
+func (q *FeedQuery) ResolveFeed(p graphql.ResolveParams) ([]Node, error) {
+	return []Node{
+		&Article{Entity: Entity{ID: "a1"}, Title: "Hello"},
+		&Author{Entity: Entity{ID: "u1"}, Name: "Ann"},
+	}, nil
+}
+
+func TestEmbeddedInterface(t *testing.T) {
+	s, err := gogql.NewSchema(&FeedQuery{},
+		gogql.WithImplementations((*Node)(nil), &Article{}, &Author{}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{
+                feed {
+                        __typename
+                        ... on Entity { id }
+                        ... on Article { title }
+                        ... on Author { name }
+                }
+        }`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{
+		"feed": []interface{}{
+			M{"__typename": "Article", "id": "a1", "title": "Hello"},
+			M{"__typename": "Author", "id": "u1", "name": "Ann"},
+		},
+	}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+	sdl := gogql.PrintSchema(s)
+	for _, def := range []string{"type Article implements Entity", "interface Entity {\n  id: String!\n}"} {
+		if !strings.Contains(sdl, def) {
+			t.Fatalf("expected %q in the SDL, got:\n%s", def, sdl)
+		}
+	}
+}
+
+type Audit struct {
+	CreatedBy string
+	UpdatedBy string
+}
+
+func (a *Audit) ResolveUpdatedBy(p graphql.ResolveParams) (string, error) {
+	return "system", nil
+}
+
+type tracking struct {
+	Source string
+}
+
+type Location struct {
+	Lat float64
+	Lng float64
+}
+
+type Shop struct {
+	Audit
+	tracking
+	Location  `graphql:"nested"`
+	Name      string
+	CreatedBy string `graphql:"alias=owner"`
+}
+
+type ShopQuery struct {
+	Shop *Shop
+}
+
+func (q *ShopQuery) ResolveShop(p graphql.ResolveParams) (*Shop, error) {
+	return &Shop{
+		Audit:     Audit{CreatedBy: "audit"},
+		tracking:  tracking{Source: "web"},
+		Location:  Location{Lat: 1, Lng: 2},
+		Name:      "Corner",
+		CreatedBy: "ann",
+	}, nil
+}
+
+type ShopOrder struct {
+	Shop     *Shop
+	Quantity int
+}
+
+type ShopMutation struct {
+	CreateShop *Shop
+	OrderShop  *Shop
+}
+
+func (m *ShopMutation) ResolveCreateShop(p graphql.ResolveParams, shop *Shop) (*Shop, error) {
+	return shop, nil
+}
+
+func (m *ShopMutation) ResolveOrderShop(p graphql.ResolveParams, order *ShopOrder) (*Shop, error) {
+	return order.Shop, nil
+}
+
+type sealed struct {
+	Seal string
+}
+
+type Parcel struct {
+	*sealed
+	Weight int
+}
+
+type ParcelMutation struct {
+	SendParcel *Parcel
+}
+
+func (m *ParcelMutation) ResolveSendParcel(p graphql.ResolveParams, parcel *Parcel) (*Parcel, error) {
+	return parcel, nil
+}
+
+func TestEmbeddedStruct(t *testing.T) {
+	s, err := gogql.NewSchema(&ShopQuery{}, gogql.WithMutation(&ShopMutation{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{
+                shop {
+                        name
+                        owner
+                        updatedBy
+                        source
+                        location { lat lng }
+                }
+        }`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{
+		"shop": M{
+			"name":      "Corner",
+			"owner":     "ann",
+			"updatedBy": "system",
+			"source":    "web",
+			"location":  M{"lat": 1.0, "lng": 2.0},
+		},
+	}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	rs := `mutation {
+                createShop(name: "Corner", owner: "ann", updatedBy: "bob", source: "app", location: {lat: 3, lng: 4}) {
+                        name
+                        owner
+                        source
+                        location { lat lng }
+                }
+                orderShop(shop: {name: "Kiosk", source: "phone"}, quantity: 2) {
+                        name
+                        source
+                }
+        }`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: rs})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e = M{
+		"createShop": M{
+			"name":     "Corner",
+			"owner":    "ann",
+			"source":   "app",
+			"location": M{"lat": 3.0, "lng": 4.0},
+		},
+		"orderShop": M{
+			"name":   "Kiosk",
+			"source": "phone",
+		},
+	}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, mutation: %v, result: %v", rs, testutil.Diff(e, r.Data))
+	}
+
+	_, err = gogql.NewSchema(&ShopQuery{}, gogql.WithMutation(&ParcelMutation{}))
+	if err == nil || !strings.Contains(err.Error(), "Parcel.Seal: cannot be set through the unexported embedded pointer sealed") {
+		t.Fatalf("expected an unexported embedded pointer error, got %v", err)
+	}
+}
+
+type contextKey string
+
+type GreetArgs struct {
+	Name string
+}
+
+type ContextQuery struct {
+	Greeting string
+	Tenant   string
+}
+
+func (q *ContextQuery) ResolveGreeting(ctx context.Context, args GreetArgs) (string, error) {
+	return fmt.Sprintf("hello %s from %v", args.Name, ctx.Value(contextKey("tenant"))), nil
+}
+
+func (q *ContextQuery) ResolveTenant(ctx context.Context) (string, error) {
+	return ctx.Value(contextKey("tenant")).(string), nil
+}
+
+func TestContextResolvers(t *testing.T) {
+	s, err := gogql.NewSchema(&ContextQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.WithValue(context.Background(), contextKey("tenant"), "acme")
+	q := `{greeting(name: "ann") tenant}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, Context: ctx})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"greeting": "hello ann from acme", "tenant": "acme"}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q, Context: cancelled})
+	if len(r.Errors) == 0 || r.Errors[0].Message != context.Canceled.Error() {
+		t.Fatalf("expected the cancellation to be reported, got: %+v", r.Errors)
+	}
+}
+
+type BookFilter struct {
+	Tag   string
+	Pages int `graphql:"default=100"`
+}
+
+type ListBooksArgs struct {
+	Limit  int        `graphql:"default=20"`
+	Order  Order      `graphql:"default=DESC"`
+	Filter BookFilter `graphql:"default={tag: \"go\"}"`
+}
+
+type ShelfQuery struct {
+	Books string
+}
+
+func (q *ShelfQuery) ResolveBooks(p graphql.ResolveParams, args *ListBooksArgs) (string, error) {
+	return fmt.Sprintf("%d %s %s %d", args.Limit, args.Order, args.Filter.Tag, args.Filter.Pages), nil
+}
+
+type BadDefaultArgs struct {
+	Limit int `graphql:"default=many"`
+}
+
+type BadDefaultQuery struct {
+	Books string
+}
+
+func (q *BadDefaultQuery) ResolveBooks(p graphql.ResolveParams, args *BadDefaultArgs) (string, error) {
+	return "", nil
+}
+
+type PriceRange struct {
+	Min    int
+	Within *PriceRange `graphql:"default={min: 1}"`
+}
+
+type PricesArgs struct {
+	Range PriceRange
+}
+
+type PriceQuery struct {
+	Prices string
+}
+
+func (q *PriceQuery) ResolvePrices(p graphql.ResolveParams, args *PricesArgs) (string, error) {
+	return fmt.Sprint(args.Range.Within.Min), nil
+}
+
+type BadLongArgs struct {
+	Total int64 `graphql:"default=\"many\""`
+}
+
+type BadLongQuery struct {
+	Books string
+}
+
+func (q *BadLongQuery) ResolveBooks(p graphql.ResolveParams, args *BadLongArgs) (string, error) {
+	return "", nil
+}
+
+func TestDefaultValues(t *testing.T) {
+	b := builder.New()
+	s, err := b.Schema(&ShelfQuery{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for q, books := range map[string]string{
+		`{books}`: "20 DESC go 100",
+		`{books(limit: 5, order: ASC, filter: {tag: "sql"})}`: "5 ASC sql 100",
+	} {
+		r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+		if len(r.Errors) > 0 {
+			t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+		}
+		e := M{"books": books}
+		if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+			t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+		}
+	}
+
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sdl, `books(limit: Int = 20, order: Order = DESC, filter: BookFilterInput = {pages: 100, tag: "go"}): String`) ||
+		!strings.Contains(sdl, "  pages: Int = 100\n") {
+		t.Fatalf("Bad SDL, got:\n%s", sdl)
+	}
+
+	_, err = gogql.NewSchema(&BadDefaultQuery{})
+	if err == nil || !strings.Contains(err.Error(), "gogql_test.BadDefaultArgs.Limit: invalid default value") {
+		t.Fatalf("expected an invalid default error, got: %v", err)
+	}
+
+	_, err = gogql.NewSchema(&BadLongQuery{}, gogql.WithIntegerMapping(builder.MapToLong, reflect.Int64))
+	if err == nil || !strings.Contains(err.Error(), `gogql_test.BadLongArgs.Total: invalid default value: invalid Long "many"`) {
+		t.Fatalf("expected an invalid Long default error, got: %v", err)
+	}
+
+	s, err = gogql.NewSchema(&PriceQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: `{prices(range: {min: 5})}`})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	if e := (M{"prices": "1"}); !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result: %v", testutil.Diff(e, r.Data))
+	}
+}
+
+type PostFilter struct {
+	Author string
+	Tag    string `graphql:"deprecated=Use author"`
+}
+
+type PostsArgs struct {
+	Order  Order
+	Sort   string `graphql:"deprecated=Use order"`
+	Filter PostFilter
+}
+
+type PostQuery struct {
+	Title    string
+	Headline string `graphql:"deprecated=Use title"`
+	Posts    int
+}
+
+func (q *PostQuery) ResolvePosts(p graphql.ResolveParams, args PostsArgs) (int, error) {
+	return 0, nil
+}
+
+func TestDeprecation(t *testing.T) {
+	warnings := make([]string, 0)
+	warnf := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	s, err := gogql.NewSchema(&PostQuery{}, gogql.WithWarnings(warnf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{__type(name: "Query") {fields(includeDeprecated: true) {name isDeprecated deprecationReason}}}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{
+		"__type": M{"fields": []interface{}{
+			M{"name": "headline", "isDeprecated": true, "deprecationReason": "Use title"},
+			M{"name": "posts", "isDeprecated": false, "deprecationReason": nil},
+			M{"name": "title", "isDeprecated": false, "deprecationReason": nil},
+		}},
+	}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	expected := []string{
+		"github.com/cipriantarta/gogql_test.PostQuery.Headline: deprecated: Use title",
+		"github.com/cipriantarta/gogql_test.Order.RANDOM: deprecated: Use ASC or DESC",
+		"github.com/cipriantarta/gogql_test.PostFilter.Tag: deprecated: Use author",
+		"github.com/cipriantarta/gogql_test.PostsArgs.Sort: deprecated: Use order",
+	}
+	for _, w := range expected {
+		found := false
+		for _, warning := range warnings {
+			found = found || warning == w
+		}
+		if !found {
+			t.Fatalf("missing warning %q in %q", w, warnings)
+		}
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("expected %d warnings, got %q", len(expected), warnings)
+	}
+
+	b := builder.New()
+	if _, err := b.Schema(&PostQuery{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`  headline: String @deprecated(reason: "Use title")`,
+		`  posts(order: Order, sort: String @deprecated(reason: "Use order"), filter: PostFilterInput): Int`,
+		`  tag: String @deprecated(reason: "Use author")`,
+	} {
+		if !strings.Contains(sdl, line+"\n") {
+			t.Fatalf("missing %q in SDL:\n%s", line, sdl)
+		}
+	}
+}
+
+type TypoArgs struct {
+	Name string `graphql:"alais=title"`
+}
+
+type TypoQuery struct {
+	Title string   `graphql:"requried"`
+	Books []*Photo `relay:"keys=ID"`
+}
+
+func (q *TypoQuery) ResolveTitle(p graphql.ResolveParams, args TypoArgs) (string, error) {
+	return "", nil
+}
+
+func TestTagErrors(t *testing.T) {
+	_, err := gogql.NewSchema(&TypoQuery{})
+	expected := `schema has 3 errors:
+	github.com/cipriantarta/gogql_test.TypoQuery.Title: invalid graphql tag: unknown option "requried"
+	github.com/cipriantarta/gogql_test.TypoArgs.Name: invalid graphql tag: unknown option "alais"
+	github.com/cipriantarta/gogql_test.TypoQuery.Books: invalid relay tag: unknown option "keys"`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%v", expected, err)
+	}
+}
+
+type apiNaming struct {
+	builder.SnakeCaseNaming
+}
+
+// InputTypeName - prefixed by the verb of the operation, e.g. CreateUserInput
+func (apiNaming) InputTypeName(t reflect.Type, field string) string {
+	verb := field
+	if i := strings.IndexFunc(field[1:], unicode.IsUpper); i >= 0 {
+		verb = field[:i+1]
+	}
+	return verb + t.Name() + "Input"
+}
+
+func (apiNaming) ConnectionTypeName(t reflect.Type) string {
+	return t.Name() + "Page"
+}
+
+type FleetMutation struct {
+	CreateVehicle *Vehicle
+	UpdateVehicle *Vehicle
+}
+
+func (m *FleetMutation) ResolveCreateVehicle(p graphql.ResolveParams, data *Vehicle) (*Vehicle, error) {
+	return data, nil
+}
+
+func (m *FleetMutation) ResolveUpdateVehicle(p graphql.ResolveParams, data *Vehicle) (*Vehicle, error) {
+	return data, nil
+}
+
+type Residence struct {
+	City string
+}
+
+type Resident struct {
+	Name      string
+	Residence *Residence
+}
+
+type ResidentArgs struct {
+	Resident *Resident
+}
+
+type CensusMutation struct {
+	CreateResident *Resident
+}
+
+func (m *CensusMutation) ResolveCreateResident(p graphql.ResolveParams, args *ResidentArgs) (*Resident, error) {
+	return args.Resident, nil
+}
+
+func TestNamingStrategy(t *testing.T) {
+	b := builder.New()
+	b.Naming = apiNaming{}
+	s, err := b.Schema(&Query{}, &Mutation{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `mutation {
+		create_user(email: "ann@example.com", password: "secret") { id email }
+	}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, RootObject: M{}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"create_user": M{"id": "1", "email": "ann@example.com"}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"input CreateVehicleAttributesInput {",
+		"  create_vehicle(make: String, model: String, attributes: CreateVehicleAttributesInput): Vehicle",
+		"  user_connection(first: Int, last: Int, before: String, after: String, limit: Int): UserPage!",
+		"type UserPage implements IConnection {\n  pageInfo: PageInfo!\n  edges: [UserEdge]!\n}",
+		"type UserEdge implements IEdge {\n  node: UserNode!\n  cursor: String!\n}",
+	} {
+		if !strings.Contains(sdl, line+"\n") {
+			t.Fatalf("missing %q in SDL:\n%s", line, sdl)
+		}
+	}
+
+	b = builder.New()
+	b.Naming = apiNaming{}
+	if _, err := b.Schema(&Query{}, &FleetMutation{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	sdl, err = b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"input CreateVehicleAttributesInput {",
+		"input UpdateVehicleAttributesInput {",
+		"  update_vehicle(make: String, model: String, attributes: UpdateVehicleAttributesInput): Vehicle",
+	} {
+		if !strings.Contains(sdl, line+"\n") {
+			t.Fatalf("missing %q in SDL:\n%s", line, sdl)
+		}
+	}
+
+	b = builder.New()
+	b.Naming = apiNaming{}
+	s, err = b.Schema(&Query{}, &CensusMutation{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q = `mutation {
+		create_resident(resident: {name: "ann", residence: {city: "Oslo"}}) { name residence { city } }
+	}`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q, RootObject: M{}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e = M{"create_resident": M{"name": "ann", "residence": M{"city": "Oslo"}}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+	sdl, err = b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"input CreateResidentInput {\n  name: String\n  residence: CreateResidenceInput\n}",
+		"input CreateResidenceInput {\n  city: String\n}",
+		"  create_resident(resident: CreateResidentInput): Resident",
+	} {
+		if !strings.Contains(sdl, line+"\n") {
+			t.Fatalf("missing %q in SDL:\n%s", line, sdl)
+		}
+	}
+}
+
+type Time struct {
+	Hour int
+}
+
+type PageInfo struct {
+	Total int
+}
+
+type CatalogQuery struct {
+	Opening  *Time
+	Updated  time.Time
+	PageInfo *PageInfo
+	Users    []*User `relay:"method=String"`
+}
+
+func (q *CatalogQuery) ResolveUsers(p graphql.ResolveParams, pageArgs *types.PageArguments) ([]*User, error) {
+	return []*User{{ID: 1}}, nil
+}
+
+func TestTypeNameCollisions(t *testing.T) {
+	_, err := gogql.NewSchema(&CatalogQuery{})
+	expected := `schema has 1 error:
+	github.com/cipriantarta/gogql/pkg/builder.PageInfo: GraphQL type PageInfo is already built from github.com/cipriantarta/gogql_test.PageInfo. Use a package qualified naming strategy such as builder.QualifiedNaming or register one of them explicitly`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%v", expected, err)
+	}
+
+	b := builder.New()
+	b.Naming = builder.QualifiedNaming{}
+	if _, err := b.Schema(&CatalogQuery{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"  opening: GogqlTestTime",
+		"  updated: DateTime",
+		"  pageInfo: GogqlTestPageInfo",
+		"  users(first: Int, last: Int, before: String, after: String, limit: Int): GogqlTestUserConnection!",
+		"type PageInfo implements IPageInfo {",
+	} {
+		if !strings.Contains(sdl, line+"\n") {
+			t.Fatalf("missing %q in SDL:\n%s", line, sdl)
+		}
+	}
+}
+
+type Member struct {
+	Name    string
+	Friends []*Member
+}
+
+type Post struct {
+	Title    string
+	Comments []*Comment
+}
+
+type Comment struct {
+	Text string
+	Post *Post
+}
+
+type Category struct {
+	Name     string
+	Children []*Category
+}
+
+type CategoryArgs struct {
+	Tree Category
+}
+
+type GraphQuery struct {
+	Member   *Member
+	Post     *Post
+	Category string
+}
+
+func (q *GraphQuery) ResolveMember(p graphql.ResolveParams) (*Member, error) {
+	ann := &Member{Name: "Ann"}
+	bob := &Member{Name: "Bob", Friends: []*Member{ann}}
+	ann.Friends = []*Member{bob}
+	return ann, nil
+}
+
+func (q *GraphQuery) ResolvePost(p graphql.ResolveParams) (*Post, error) {
+	post := &Post{Title: "Cycles"}
+	post.Comments = []*Comment{{Text: "Nice", Post: post}}
+	return post, nil
+}
+
+func (q *GraphQuery) ResolveCategory(p graphql.ResolveParams, args CategoryArgs) (string, error) {
+	names := make([]string, 0)
+	var walk func(c Category)
+	walk = func(c Category) {
+		names = append(names, c.Name)
+		for _, child := range c.Children {
+			walk(*child)
+		}
+	}
+	walk(args.Tree)
+	return strings.Join(names, " "), nil
+}
+
+func TestCyclicTypes(t *testing.T) {
+	s, err := gogql.NewSchema(&GraphQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{
+		member { name friends { name friends { name } } }
+		post { title comments { text post { title } } }
+		category(tree: {name: "a", children: [{name: "b", children: [{name: "c"}]}, {name: "d"}]})
+	}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{
+		"member": M{"name": "Ann", "friends": []interface{}{
+			M{"name": "Bob", "friends": []interface{}{M{"name": "Ann"}}},
+		}},
+		"post": M{"title": "Cycles", "comments": []interface{}{
+			M{"text": "Nice", "post": M{"title": "Cycles"}},
+		}},
+		"category": "a b c d",
+	}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+}
+
+type Track struct {
+	Title    string
+	Subtitle *string
+	Length   int
+	Tags     []string
+	Credits  []*Person
+	Labels   []*string `graphql:"required,elem_required"`
+	Album    *Photo
+	Cover    Photo
+	Sleeve   Photo
+	Samples  []Photo
+}
+
+func (t *Track) ResolveSleeve(p graphql.ResolveParams) (*Photo, error) {
+	return nil, nil
+}
+
+func (t *Track) ResolveSamples(p graphql.ResolveParams) ([]*Photo, error) {
+	return []*Photo{nil, {Width: 1}}, nil
+}
+
+type TrackArgs struct {
+	Tags []string `graphql:"elem_required"`
+}
+
+type TrackQuery struct {
+	Track *Track
+}
+
+func (q *TrackQuery) ResolveTrack(p graphql.ResolveParams, args TrackArgs) (*Track, error) {
+	return &Track{Title: "Intro", Tags: args.Tags, Labels: []*string{}}, nil
+}
+
+type BadElemQuery struct {
+	Title string `graphql:"elem_required"`
+}
+
+func TestNullabilityInference(t *testing.T) {
+	b := builder.New()
+	b.InferNullability = true
+	s, err := b.Schema(&TrackQuery{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{track(tags: ["a", "b"]) {title length tags sleeve {width} samples {width}}}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"track": M{
+		"title":   "Intro",
+		"length":  0,
+		"tags":    []interface{}{"a", "b"},
+		"sleeve":  nil,
+		"samples": []interface{}{nil, M{"width": 1}},
+	}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `type Query {
+  track(tags: [String!]): Track
+}
+
+type Track {
+  title: String!
+  subtitle: String
+  length: Int!
+  tags: [String!]
+  credits: [Person]
+  labels: [String!]!
+  album: Photo
+  cover: Photo!
+  sleeve: Photo
+  samples: [Photo]
+}
+`
+	if !strings.HasSuffix(sdl, expected) {
+		t.Fatalf("Bad SDL, expected:\n%s\ngot:\n%s", expected, sdl)
+	}
+
+	_, err = gogql.NewSchema(&BadElemQuery{}, gogql.WithNullabilityInference())
+	if err == nil || !strings.Contains(err.Error(), "BadElemQuery.Title: elem_required is only valid on lists") {
+		t.Fatalf("expected an elem_required error, got: %v", err)
+	}
+}
+
+type Profile struct {
+	First string
+	Last  string
+}
+
+type AvatarArgs struct {
+	Size int `graphql:"default=64"`
+}
+
+func (p *Profile) ResolveFullName(params graphql.ResolveParams) (string, error) {
+	return p.First + " " + p.Last, nil
+}
+
+func (p *Profile) ResolveAvatarURL(params graphql.ResolveParams, args AvatarArgs) (string, error) {
+	return fmt.Sprintf("https://example.com/%s.png?size=%d", strings.ToLower(p.First), args.Size), nil
+}
+
+// Resolve - not a field, as it names none
+func (p *Profile) Resolve() {}
+
+type ProfileQuery struct {
+	Profile *Profile
+}
+
+func (q *ProfileQuery) ResolveProfile(params graphql.ResolveParams) (*Profile, error) {
+	return &Profile{First: "Ann", Last: "Lee"}, nil
+}
+
+type Revision struct {
+	Author string
+}
+
+func (r *Revision) ResolveReviewer(params graphql.ResolveParams) (string, error) {
+	return "", nil
+}
+
+type Essay struct {
+	Revision `graphql:"-"`
+	Title    string
+	draft    bool
+}
+
+// ResolveDraft - resolves the unexported field, which declares no field
+func (e *Essay) ResolveDraft(params graphql.ResolveParams) (bool, error) {
+	return e.draft, nil
+}
+
+type EssayQuery struct {
+	Essay *Essay
+}
+
+type Badge struct {
+	Label string `graphql:"alias=name"`
+}
+
+func (b *Badge) ResolveName(params graphql.ResolveParams) (string, error) {
+	return "", nil
+}
+
+func (b *Badge) ResolveColor(params graphql.ResolveParams) string {
+	return ""
+}
+
+type BadgeQuery struct {
+	Badge *Badge
+}
+
+type BadgeMutation struct {
+	Award *Badge
+}
+
+func (m *BadgeMutation) ResolveAward(params graphql.ResolveParams, args *BadgeArgs) (*Badge, error) {
+	return args.Badge, nil
+}
+
+type BadgeArgs struct {
+	Badge *Badge
+}
+
+func TestComputedFields(t *testing.T) {
+	b := builder.New()
+	s, err := b.Schema(&ProfileQuery{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{profile {fullName small: avatarURL(size: 32) avatarURL}}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"profile": M{
+		"fullName":  "Ann Lee",
+		"small":     "https://example.com/ann.png?size=32",
+		"avatarURL": "https://example.com/ann.png?size=64",
+	}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `type Profile {
+  first: String
+  last: String
+  avatarURL(size: Int = 64): String
+  fullName: String
+}
+`
+	if !strings.HasPrefix(sdl, expected) {
+		t.Fatalf("Bad SDL, expected:\n%s\ngot:\n%s", expected, sdl)
+	}
+
+	b = builder.New()
+	if _, err := b.Schema(&EssayQuery{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	sdl, err = b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `type Essay {
+  title: String
+}
+`
+	if !strings.HasPrefix(sdl, expected) {
+		t.Fatalf("Bad SDL, expected:\n%s\ngot:\n%s", expected, sdl)
+	}
+
+	_, err = gogql.NewSchema(&BadgeQuery{}, gogql.WithMutation(&BadgeMutation{}))
+	schemaErr, ok := err.(*gogql.SchemaError)
+	if !ok || len(schemaErr.Errors) != 2 ||
+		!strings.Contains(err.Error(), "gogql_test.Badge.Name: field name is already declared") ||
+		!strings.Contains(err.Error(), "gogql_test.Badge.ResolveColor: expected two output params, got 1") {
+		t.Fatalf("expected a name collision and a resolver error, got: %v", err)
+	}
+}
+
+type Document struct {
+	Title  string
+	Meta   map[string]interface{}
+	Extra  interface{}
+	Raw    json.RawMessage
+	Labels map[string]string
+}
+
+type SaveDocumentArgs struct {
+	Meta   map[string]interface{}
+	Extra  interface{}
+	Raw    json.RawMessage
+	Labels map[string]string
+}
+
+type DocumentQuery struct {
+	Document *Document
+}
+
+func (q *DocumentQuery) ResolveDocument(p graphql.ResolveParams, args SaveDocumentArgs) (*Document, error) {
+	return &Document{Title: "Notes", Meta: args.Meta, Extra: args.Extra, Raw: args.Raw, Labels: args.Labels}, nil
+}
+
+func TestJSONScalar(t *testing.T) {
+	s, err := gogql.NewSchema(&DocumentQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `query ($raw: JSON) {
+		document(
+			meta: {tags: ["a", "b"], pages: 3, ratio: 0.5, draft: true}
+			extra: "plain"
+			raw: $raw
+			labels: {env: "prod"}
+		) { title meta extra raw labels }
+	}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, VariableValues: M{"raw": M{"nested": []interface{}{1, "x"}}}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"document": M{
+		"title":  "Notes",
+		"meta":   M{"tags": []interface{}{"a", "b"}, "pages": 3, "ratio": 0.5, "draft": true},
+		"extra":  "plain",
+		"raw":    M{"nested": []interface{}{float64(1), "x"}},
+		"labels": map[string]string{"env": "prod"},
+	}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+}
+
+type Booking struct {
+	ID      [16]byte
+	Day     types.Date
+	Start   types.LocalTime
+	Length  time.Duration
+	Link    *url.URL
+	Client  net.IP
+	Deposit big.Int
+	Price   big.Float
+	Contact types.Email
+}
+
+type BookArgs struct {
+	ID      types.UUID
+	Day     types.Date
+	Start   types.LocalTime
+	Length  time.Duration
+	Link    *url.URL
+	Client  net.IP
+	Deposit big.Int
+	Price   *big.Float
+	Contact types.Email
+}
+
+type BookingQuery struct {
+	Booking *Booking
+}
+
+func (q *BookingQuery) ResolveBooking(p graphql.ResolveParams, args BookArgs) (*Booking, error) {
+	return &Booking{
+		ID:      args.ID,
+		Day:     args.Day,
+		Start:   args.Start,
+		Length:  args.Length,
+		Link:    args.Link,
+		Client:  args.Client,
+		Deposit: args.Deposit,
+		Price:   *args.Price,
+		Contact: args.Contact,
+	}, nil
+}
+
+func TestExtendedScalars(t *testing.T) {
+	b := builder.New()
+	scalars.Register(b)
+	s, err := b.Schema(&BookingQuery{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `query ($price: Decimal) {
+		booking(
+			id: "123E4567-e89b-12d3-a456-426614174000"
+			day: "2024-02-29"
+			start: "13:45:00.250"
+			length: "1h30m"
+			link: "https://example.com/rooms/4"
+			client: "2001:db8::1"
+			deposit: 123456789012345678901234567890
+			price: $price
+			contact: "ann@example.com"
+		) { id day start length link client deposit price contact }
+	}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, VariableValues: M{"price": "1234.50"}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"booking": M{
+		"id":      "123e4567-e89b-12d3-a456-426614174000",
+		"day":     "2024-02-29",
+		"start":   "13:45:00.25",
+		"length":  "1h30m0s",
+		"link":    "https://example.com/rooms/4",
+		"client":  "2001:db8::1",
+		"deposit": "123456789012345678901234567890",
+		"price":   "1234.5",
+		"contact": "ann@example.com",
+	}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	q = `{ booking(day: "2024-02-30", price: 1) { day } }`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) != 1 || !strings.Contains(r.Errors[0].Message, `invalid Date "2024-02-30": day out of range`) {
+		t.Fatalf("expected a precise parse error, got %+v", r.Errors)
+	}
+
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`scalar Date @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc3339#section-5.6")`,
+		`scalar UUID @specifiedBy(url: "https://www.rfc-editor.org/rfc/rfc4122")`,
+		"  client: IP\n",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Fatalf("expected %q in:\n%s", want, sdl)
+		}
+	}
+}
+
+type Counter struct {
+	Hits   int64
+	Views  uint64
+	Shards []uint32
+	Small  int32
+}
+
+type CounterArgs struct {
+	Hits  int64
+	Views uint64
+	Small int32
+}
+
+type CounterQuery struct {
+	Counter *Counter
+	Legacy  *Counter
+}
+
+func (q *CounterQuery) ResolveCounter(p graphql.ResolveParams, args CounterArgs) (*Counter, error) {
+	return &Counter{Hits: args.Hits, Views: args.Views, Shards: []uint32{1, 2}, Small: args.Small}, nil
+}
+
+func (q *CounterQuery) ResolveLegacy(p graphql.ResolveParams) (*Counter, error) {
+	return &Counter{Hits: 1 << 40, Views: 1, Shards: []uint32{1 << 31}}, nil
+}
+
+func TestIntegerMapping(t *testing.T) {
+	s, err := gogql.NewSchema(&CounterQuery{}, gogql.WithIntegerMapping(builder.MapToLongString, reflect.Int64, reflect.Uint64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `query ($views: Long) { counter(hits: "9007199254740993", views: $views, small: 7) { hits views shards small } }`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, VariableValues: M{"views": "9223372036854775807"}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"counter": M{"hits": "9007199254740993", "views": "9223372036854775807", "shards": []interface{}{1, 2}, "small": 7}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	for q, want := range map[string]string{
+		`{ counter(hits: "9223372036854775808") { hits } }`: "9223372036854775808 overflows Long",
+		`{ counter(views: "-1") { views } }`:                "-1 overflows uint64",
+		`{ legacy { shards } }`:                             "2147483648 overflows Int",
+	} {
+		r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+		if len(r.Errors) != 1 || !strings.Contains(r.Errors[0].Message, want) {
+			t.Fatalf("expected %q for %s, got %+v", want, q, r.Errors)
+		}
+	}
+
+	_, err = gogql.NewSchema(&CounterQuery{},
+		gogql.WithIntegerMapping(builder.MapToLong, reflect.Int64),
+		gogql.WithIntegerMapping(builder.MapToLongString, reflect.Uint64))
+	if err == nil || !strings.Contains(err.Error(), "Long cannot be serialized both as a number and as a string") {
+		t.Fatalf("expected a Long serialization conflict, got %v", err)
+	}
+}
+
+type Event struct {
+	At time.Time
+}
+
+type EventQuery struct {
+	Event *Event
+}
+
+func TestBuilderIsolation(t *testing.T) {
+	unix := graphql.NewScalar(graphql.ScalarConfig{
+		Name: "Unix",
+		Serialize: func(value interface{}) interface{} {
+			return value.(time.Time).Unix()
+		},
+	})
+	custom := builder.New()
+	custom.TypeScalar(time.Time{}, unix)
+	if _, err := custom.Schema(&EventQuery{}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	s, err := gogql.NewSchema(&EventQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.TypeMap()["Unix"]; ok {
+		t.Fatal("a scalar registered on one builder leaked into another")
+	}
+
+	errs := make(chan error, 8)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			_, err := gogql.NewSchema(&Query{}, gogql.WithExtendedScalars())
+			errs <- err
+		}()
+	}
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+type Blob []byte
+
+type Attachment struct {
+	Name  string
+	Data  []byte
+	Thumb Blob
+}
+
+type AttachArgs struct {
+	File  Attachment
+	Extra *Blob
+}
+
+type AttachmentQuery struct {
+	Attachment *Attachment
+}
+
+func (q *AttachmentQuery) ResolveAttachment(p graphql.ResolveParams, args AttachArgs) (*Attachment, error) {
+	file := args.File
+	if args.Extra != nil {
+		file.Thumb = append(file.Thumb, *args.Extra...)
+	}
+	return &file, nil
+}
+
+func TestBase64Scalar(t *testing.T) {
+	s, err := gogql.NewSchema(&AttachmentQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `query ($extra: Base64) {
+		attachment(file: {name: "a.bin", data: "AP8Q", thumb: "aGk"}, extra: $extra) { name data thumb }
+	}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, VariableValues: M{"extra": "IQ=="}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"attachment": M{"name": "a.bin", "data": "AP8Q", "thumb": "aGkh"}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	q = `{ attachment(file: {data: "not base64!"}) { data } }`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) != 1 || !strings.Contains(r.Errors[0].Message, "invalid Base64 value") {
+		t.Fatalf("expected an invalid Base64 value, got %+v", r.Errors)
+	}
+
+	s, err = gogql.NewSchema(&AttachmentQuery{}, gogql.WithBase64URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q = `{ attachment(file: {data: "-_8"}) { data } }`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	e = M{"attachment": M{"data": "-_8="}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v %v", q, testutil.Diff(e, r.Data), r.Errors)
+	}
+}
+
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+func (m Money) MarshalGraphQL() (interface{}, error) {
+	return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency), nil
+}
+
+func (m *Money) UnmarshalGraphQL(value interface{}) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("money must be a string")
+	}
+	var units, cents int64
+	if _, err := fmt.Sscanf(s, "%d.%d %s", &units, &cents, &m.Currency); err != nil {
+		return fmt.Errorf("invalid money %q", s)
+	}
+	m.Cents = units*100 + cents
+	return nil
+}
+
+type Sku string
+
+func (s Sku) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(s))), nil
+}
+
+func (s *Sku) UnmarshalText(text []byte) error {
+	if len(text) != 6 {
+		return fmt.Errorf("invalid sku %q, expected 6 characters", text)
+	}
+	*s = Sku(strings.ToLower(string(text)))
+	return nil
+}
+
+type Product struct {
+	Sku   Sku
+	Price Money
+	Tags  []Sku
+}
+
+type PriceArgs struct {
+	Sku      Sku
+	Price    *Money
+	Discount Money `graphql:"default=\"0.00 EUR\""`
+}
+
+type ProductQuery struct {
+	Product *Product
+}
+
+func (q *ProductQuery) ResolveProduct(p graphql.ResolveParams, args PriceArgs) (*Product, error) {
+	price := *args.Price
+	price.Cents -= args.Discount.Cents
+	return &Product{Sku: args.Sku, Price: price, Tags: []Sku{args.Sku, "abc123"}}, nil
+}
+
+type BalanceQuery struct {
+	Money
+	Owner string
+}
+
+func TestMarshalerScalars(t *testing.T) {
+	s, err := gogql.NewSchema(&ProductQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `query ($price: Money) { product(sku: "ab12cd", price: $price, discount: "1.25 EUR") { sku price tags } }`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, VariableValues: M{"price": "12.50 EUR"}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"product": M{"sku": "AB12CD", "price": "11.25 EUR", "tags": []interface{}{"AB12CD", "ABC123"}}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	q = `{ product(sku: "abc", price: "1.00 EUR") { sku } }`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) != 1 || !strings.Contains(r.Errors[0].Message, `invalid sku "abc", expected 6 characters`) {
+		t.Fatalf("expected the error of the unmarshaler, got %+v", r.Errors)
+	}
+
+	s, err = gogql.NewSchema(&BalanceQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q = `{ money owner }`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q, RootObject: M{"money": Money{Cents: 250, Currency: "EUR"}, "owner": "Ann"}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e = M{"money": "2.50 EUR", "owner": "Ann"}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+}
+
+type Contact struct {
+	Name  string
+	Email *string
+	Phone *string
+}
+
+type ContactPatch struct {
+	Email *string
+	Phone *string
+}
+
+type PatchContactArgs struct {
+	Name  string
+	Patch ContactPatch
+	Note  *string
+}
+
+type ContactQuery struct {
+	Contact *Contact
+	Sent    []string
+}
+
+func (q *ContactQuery) ResolveContact(p graphql.ResolveParams, args PatchContactArgs, mask types.FieldMask) (*Contact, error) {
+	email, phone := "old@example.com", "555-0100"
+	c := &Contact{Name: args.Name, Email: &email, Phone: &phone}
+	if mask.Has("patch.email") {
+		c.Email = args.Patch.Email
+	}
+	if mask.Has("patch.phone") {
+		c.Phone = args.Patch.Phone
+	}
+	return c, nil
+}
+
+func (q *ContactQuery) ResolveSent(p graphql.ResolveParams, args PatchContactArgs, mask types.FieldMask) ([]string, error) {
+	sent := make([]string, 0)
+	for _, path := range []string{"name", "patch", "patch.email", "patch.phone", "note"} {
+		if mask.Has(path) {
+			sent = append(sent, fmt.Sprintf("%s:%v", path, mask.IsNull(path)))
+		}
+	}
+	return sent, nil
+}
+
+func TestFieldMask(t *testing.T) {
+	s, err := gogql.NewSchema(&ContactQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `query ($note: String, $patch: ContactPatchInput) {
+		contact(name: "Ann", patch: {email: "ann@example.com"}) { email phone }
+		sent(name: "Ann", patch: $patch, note: $note)
+	}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, VariableValues: M{"note": nil, "patch": M{"phone": "555-0199"}}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{
+		"contact": M{"email": "ann@example.com", "phone": "555-0100"},
+		"sent":    []interface{}{"name:false", "patch:false", "patch.phone:false"},
+	}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	q = `query ($note: String, $patch: ContactPatchInput) {
+		sent(name: "Ann", patch: $patch, note: $note) ...on Query { sent(name: "Ann", patch: $patch, note: $note) }
+	}`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q, VariableValues: M{"note": "hi"}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e = M{"sent": []interface{}{"name:false", "note:false"}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	for _, c := range []struct {
+		query     string
+		variables M
+		expected  M
+	}{
+		{
+			query: `{
+				contact(name: "Ann", patch: {email: null}) { email phone }
+				sent(name: "Ann", patch: {email: null}, note: null)
+			}`,
+			expected: M{
+				"contact": M{"email": nil, "phone": "555-0100"},
+				"sent":    []interface{}{"name:false", "patch:false", "patch.email:true", "note:true"},
+			},
+		},
+		{
+			query: `query ($note: String, $patch: ContactPatchInput) {
+				sent(name: "Ann", patch: $patch, note: $note)
+			}`,
+			variables: M{"note": nil, "patch": M{"email": nil}},
+			expected: M{
+				"sent": []interface{}{"name:false", "patch:false", "patch.email:true", "note:true"},
+			},
+		},
+		{
+			query: `query Sent($note: String) { ...sent }
+			fragment sent on Query { sent(name: "Ann", patch: {phone: null, email: "ann@example.com"}, note: $note) }`,
+			variables: M{"note": "hi"},
+			expected: M{
+				"sent": []interface{}{"name:false", "patch:false", "patch.email:false", "patch.phone:true", "note:false"},
+			},
+		},
+	} {
+		r = gogql.Do(graphql.Params{Schema: *s, RequestString: c.query, VariableValues: c.variables})
+		if len(r.Errors) > 0 {
+			t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+		}
+		if !testutil.EqualResults(&graphql.Result{Data: c.expected}, r) {
+			t.Fatalf("Bad result, query: %v, result: %v", c.query, testutil.Diff(c.expected, r.Data))
+		}
+	}
+
+	s, err = gogql.NewSchema(&Query{}, gogql.WithMutation(&Mutation{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q = `mutation { createUser(email: null, password: "secret") { id } }`
+	r = gogql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) != 1 || r.Errors[0].Message != `Expected type "String!", found null.` {
+		t.Fatalf("expected a non-null error, got %+v", r.Errors)
+	}
+}
+
+type Locale string
+
+type Translations map[Locale]string
+
+type Listing struct {
+	Title        string
+	Labels       map[string]string `graphql:"entries"`
+	Stock        map[int]int       `graphql:"entries"`
+	Translations Translations      `graphql:"entries"`
+	Meta         map[string]interface{}
+}
+
+type ListingArgs struct {
+	Labels       map[string]string `graphql:"entries"`
+	Stock        map[int]int       `graphql:"entries"`
+	Translations Translations      `graphql:"entries"`
+}
+
+type ListingQuery struct {
+	Listing *Listing
+}
+
+func (q *ListingQuery) ResolveListing(p graphql.ResolveParams, args ListingArgs) (*Listing, error) {
+	return &Listing{Title: "Lamp", Labels: args.Labels, Stock: args.Stock, Translations: args.Translations}, nil
+}
+
+func TestMapEntries(t *testing.T) {
+	b := builder.New()
+	b.Scalar("Locale", graphql.String)
+	s, err := b.Schema(&ListingQuery{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `query ($stock: [IntIntEntryInput!]) {
+		listing(
+			labels: [{key: "color", value: "red"}, {key: "size", value: "L"}]
+			stock: $stock
+			translations: [{key: "fr", value: "Lampe"}, {key: "de", value: "Lampe"}]
+		) {
+			labels { key value }
+			stock { key value }
+			translations { key value }
+		}
+	}`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q, VariableValues: M{"stock": []interface{}{M{"key": 10, "value": 1}, M{"key": 9, "value": 0}}}})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"listing": M{
+		"labels":       []interface{}{M{"key": "color", "value": "red"}, M{"key": "size", "value": "L"}},
+		"stock":        []interface{}{M{"key": 9, "value": 0}, M{"key": 10, "value": 1}},
+		"translations": []interface{}{M{"key": "de", "value": "Lampe"}, M{"key": "fr", "value": "Lampe"}},
+	}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	q = `{ listing(labels: [{key: "a", value: "1"}, {key: "a", value: "2"}]) { title } }`
+	r = graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) != 1 || !strings.Contains(r.Errors[0].Message, "duplicate entry key a") {
+		t.Fatalf("expected a duplicate entry key, got %+v", r.Errors)
+	}
+
+	sdl, err := b.SDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type StringStringEntry {\n  key: String!\n  value: String\n}",
+		"input TranslationsEntryInput {\n  key: String!\n  value: String\n}",
+		"  meta: JSON\n",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Fatalf("expected %q in:\n%s", want, sdl)
+		}
+	}
+}
+
+type Barcode string
+type Quantity int64
+
+type StockItem struct {
+	ID       ID
+	Barcode  Barcode
+	Quantity Quantity
+}
+
+type StockArgs struct {
+	ID ID
+}
+
+type StockQuery struct {
+	Item *StockItem
+}
+
+func (q *StockQuery) ResolveItem(p graphql.ResolveParams, args StockArgs) (*StockItem, error) {
+	return &StockItem{ID: args.ID, Barcode: "lamp-1", Quantity: 3}, nil
+}
+
+type BrokenItem struct {
+	Name     string
+	OnChange func()
+}
+
+type BrokenQuery struct {
+	Item *BrokenItem
+}
+
+func TestNamedBasicTypes(t *testing.T) {
+	s, err := gogql.NewSchema(&StockQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{ item(id: "42") { id barcode quantity } }`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"item": M{"id": "42", "barcode": "lamp-1", "quantity": 3}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	_, err = gogql.NewSchema(&BrokenQuery{})
+	if err == nil || !strings.Contains(err.Error(), "OnChange: no GraphQL output type for func()") {
+		t.Fatalf("expected a missing output type, got %v", err)
+	}
+}
+
+type Item struct {
+	ID   ID `graphql:"required"`
+	Name string
+}
+
+type ItemQuery struct {
+	Items []Item `relay:"method=String"`
+}
+
+func (q *ItemQuery) ResolveItems(p graphql.ResolveParams, pageArgs *types.PageArguments) ([]Item, error) {
+	return []Item{{ID: 1, Name: "lamp"}, {ID: 2, Name: "desk"}}, nil
+}
+
+type ScalarRelayQuery struct {
+	Names []string `relay:"method=String"`
+	Count int      `relay:"method=String"`
+}
+
+func TestConnectionOfStructs(t *testing.T) {
+	s, err := gogql.NewSchema(&ItemQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := `{ items { edges { node { id name } } } }`
+	r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+	if len(r.Errors) > 0 {
+		t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+	}
+	e := M{"items": M{"edges": []interface{}{
+		M{"node": M{"id": "1", "name": "lamp"}},
+		M{"node": M{"id": "2", "name": "desk"}},
+	}}}
+	if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+		t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+	}
+
+	_, err = gogql.NewSchema(&ScalarRelayQuery{})
+	if err == nil {
+		t.Fatal("expected relay fields of scalars to fail")
+	}
+	for _, want := range []string{
+		"ScalarRelayQuery.Names: relay fields must be slices of structs or of pointers to structs, got []string",
+		"ScalarRelayQuery.Count: relay fields must be slices of structs or of pointers to structs, got int",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+}
+
+type SparseItemQuery struct {
+	Items []*Item `relay:"method=String"`
+}
+
+func (q *SparseItemQuery) ResolveItems(p graphql.ResolveParams, pageArgs *types.PageArguments) ([]*Item, error) {
+	return []*Item{nil, {ID: 1, Name: "lamp"}, {ID: 2, Name: "desk"}}, nil
+}
+
+func TestConnectionLimit(t *testing.T) {
+	s, err := gogql.NewSchema(&SparseItemQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for q, e := range map[string]M{
+		`{ items(limit: 2) { pageInfo { startCursor endCursor hasMore } edges { node { name } } } }`: {"items": M{
+			"pageInfo": M{"startCursor": "MQ==", "endCursor": "MQ==", "hasMore": true},
+			"edges":    []interface{}{M{"node": M{"name": "lamp"}}},
+		}},
+		`{ items(limit: 3) { pageInfo { startCursor endCursor hasMore } edges { node { name } } } }`: {"items": M{
+			"pageInfo": M{"startCursor": "MQ==", "endCursor": "Mg==", "hasMore": false},
+			"edges":    []interface{}{M{"node": M{"name": "lamp"}}, M{"node": M{"name": "desk"}}},
+		}},
+		`{ items(limit: 0) { pageInfo { startCursor endCursor hasMore } edges { node { name } } } }`: {"items": M{
+			"pageInfo": M{"startCursor": "", "endCursor": "", "hasMore": true},
+			"edges":    []interface{}{},
+		}},
+	} {
+		r := graphql.Do(graphql.Params{Schema: *s, RequestString: q})
+		if len(r.Errors) > 0 {
+			t.Fatalf("failed to execute graphql operation, errors: %+v", r.Errors)
+		}
+		if !testutil.EqualResults(&graphql.Result{Data: e}, r) {
+			t.Fatalf("Bad result, query: %v, result: %v", q, testutil.Diff(e, r.Data))
+		}
+	}
+}
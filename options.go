@@ -0,0 +1,169 @@
+package gogql
+
+import (
+	"reflect"
+
+	"github.com/cipriantarta/gogql/pkg/builder"
+	"github.com/cipriantarta/gogql/pkg/scalars"
+)
+
+type config struct {
+	builder      *builder.Builder
+	mutation     interface{}
+	subscription interface{}
+	sdl          string
+	printTo      *string
+}
+
+// Option - configures the schema built by NewSchema
+type Option func(*config)
+
+// WithMutation - sets the mutation root object
+func WithMutation(mutation interface{}) Option {
+	return func(c *config) {
+		c.mutation = mutation
+	}
+}
+
+// WithSubscription - sets the subscription root object
+func WithSubscription(subscription interface{}) Option {
+	return func(c *config) {
+		c.subscription = subscription
+	}
+}
+
+// WithScalars - adds/replaces scalar types, keyed by Go type name, either bare,
+// e.g. `ID`, or package qualified, e.g. `github.com/acme/billing.ID`
+func WithScalars(scalars Scalars) Option {
+	return func(c *config) {
+		for k, v := range scalars {
+			c.builder.Scalar(k, v)
+		}
+	}
+}
+
+// WithExtendedScalars - maps time.Duration, dates, times of day, URLs, IP
+// addresses, big numbers, emails and UUIDs to the scalars of the scalars package
+func WithExtendedScalars() Option {
+	return func(c *config) {
+		scalars.Register(c.builder)
+	}
+}
+
+// WithIntegerMapping - maps Go integer kinds, such as reflect.Int64 and
+// reflect.Uint64, to graphql.Int or to the 64-bit Long scalar
+func WithIntegerMapping(mapping builder.IntMapping, kinds ...reflect.Kind) Option {
+	return func(c *config) {
+		for _, kind := range kinds {
+			c.builder.Integers[kind] = mapping
+		}
+	}
+}
+
+// WithBase64URL - maps byte slices to the URL safe Base64URL scalar instead of Base64
+func WithBase64URL() Option {
+	return func(c *config) {
+		c.builder.Base64URL = true
+	}
+}
+
+// WithEnums - adds/replaces enum types, keyed by Go type name
+func WithEnums(enums Enums) Option {
+	return func(c *config) {
+		for k, v := range enums {
+			c.builder.Enum(k, v)
+		}
+	}
+}
+
+// WithInterfaces - adds/replaces interface types, keyed by interface name
+func WithInterfaces(interfaces Interfaces) Option {
+	return func(c *config) {
+		for k, v := range interfaces {
+			c.builder.Interface(k, v)
+		}
+	}
+}
+
+// WithObjects - adds/replaces output object types, keyed by Go type name
+func WithObjects(objects OutputObjects) Option {
+	return func(c *config) {
+		for k, v := range objects {
+			c.builder.Object(k, v)
+		}
+	}
+}
+
+// WithInputObjects - adds/replaces input object types, keyed by Go type name
+func WithInputObjects(inputs InputObjects) Option {
+	return func(c *config) {
+		for k, v := range inputs {
+			c.builder.Input(k, v)
+		}
+	}
+}
+
+// WithPaginationLimit - sets the default page size of relay connections
+func WithPaginationLimit(limit int) Option {
+	return func(c *config) {
+		c.builder.PaginationLimit = limit
+	}
+}
+
+// WithWarnings - receives a warning for every deprecated field, argument and
+// enum value found while building the schema, e.g. `log.Printf`
+func WithWarnings(warnf func(format string, args ...interface{})) Option {
+	return func(c *config) {
+		c.builder.Warnf = warnf
+	}
+}
+
+// WithNaming - sets the naming strategy of fields, arguments, enum values and
+// generated types, e.g. `builder.SnakeCaseNaming{}`
+func WithNaming(naming builder.NamingStrategy) Option {
+	return func(c *config) {
+		c.builder.Naming = naming
+	}
+}
+
+// WithNullabilityInference - output fields and list elements of Go types which
+// cannot hold nil are non-null, without tagging them `required`
+func WithNullabilityInference() Option {
+	return func(c *config) {
+		c.builder.InferNullability = true
+	}
+}
+
+// WithSDL - binds the Go structs to a schema definition document. The document
+// is the source of truth and every difference from the Go types is an error
+func WithSDL(sdl string) Option {
+	return func(c *config) {
+		c.sdl = sdl
+	}
+}
+
+// WithPrintedSchema - stores the built schema in the schema definition
+// language, with fields in the declaration order of the Go structs, which
+// PrintSchema cannot recover from a *graphql.Schema
+func WithPrintedSchema(sdl *string) Option {
+	return func(c *config) {
+		c.printTo = sdl
+	}
+}
+
+// WithUnion - exposes fields of a Go interface type as a union of its members.
+// The interface is given as a nil pointer to it, e.g. `(*SearchResult)(nil)`
+func WithUnion(iface interface{}, members ...interface{}) Option {
+	return func(c *config) {
+		c.builder.Union(iface, members...)
+	}
+}
+
+// WithImplementations - exposes fields of a Go interface type as the GraphQL
+// interface of the embedded struct which implements it. The interface is given
+// as a nil pointer to it, e.g. `(*Node)(nil)`
+func WithImplementations(iface interface{}, members ...interface{}) Option {
+	return func(c *config) {
+		c.builder.Implementations(iface, members...)
+	}
+}
@@ -0,0 +1,308 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/iancoleman/strcase"
+)
+
+var rootTypes = map[string]bool{
+	"Query":        true,
+	"Mutation":     true,
+	"Subscription": true,
+}
+
+// Bind - builds the schema from the Go root objects and checks it against a
+// schema definition document, which is the source of truth. Types, fields,
+// arguments and enum values that exist on one side only, type mismatches and
+// fields that need a Resolve method but have none are all reported at once as
+// a *SchemaError
+func (b *Builder) Bind(sdl string, query interface{}, mutation interface{}, subscription interface{}) (*graphql.Schema, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: sdl})
+	if err != nil {
+		return nil, err
+	}
+	schema, err := b.Schema(query, mutation, subscription)
+	if err != nil {
+		return nil, err
+	}
+	b.bind(doc, schema)
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func (b *Builder) bind(doc *ast.Document, schema *graphql.Schema) {
+	typeMap := schema.TypeMap()
+	declared := make(map[string]bool)
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			b.bindRoots(d, schema)
+		case *ast.ScalarDefinition:
+			declared[d.Name.Value] = true
+			b.bindType(typeMap, d.Name.Value, "scalar")
+		case *ast.ObjectDefinition:
+			declared[d.Name.Value] = true
+			if t, ok := b.bindType(typeMap, d.Name.Value, "type").(*graphql.Object); ok {
+				b.bindInterfaces(t, d.Interfaces)
+				b.bindFields(t.Name(), t.Fields(), d.Fields)
+			}
+		case *ast.InterfaceDefinition:
+			declared[d.Name.Value] = true
+			if t, ok := b.bindType(typeMap, d.Name.Value, "interface").(*graphql.Interface); ok {
+				b.bindFields(t.Name(), t.Fields(), d.Fields)
+			}
+		case *ast.UnionDefinition:
+			declared[d.Name.Value] = true
+			if t, ok := b.bindType(typeMap, d.Name.Value, "union").(*graphql.Union); ok {
+				b.bindUnion(t, d.Types)
+			}
+		case *ast.EnumDefinition:
+			declared[d.Name.Value] = true
+			if t, ok := b.bindType(typeMap, d.Name.Value, "enum").(*graphql.Enum); ok {
+				b.bindEnum(t, d.Values)
+			}
+		case *ast.InputObjectDefinition:
+			declared[d.Name.Value] = true
+			if t, ok := b.bindType(typeMap, d.Name.Value, "input").(*graphql.InputObject); ok {
+				b.bindInputFields(t, d.Fields)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		if strings.HasPrefix(name, "__") || specifiedScalars[name] || declared[name] {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.bindFail(name, "", "type %s exists in Go but is not declared in SDL", name)
+	}
+}
+
+func (b *Builder) bindRoots(d *ast.SchemaDefinition, schema *graphql.Schema) {
+	roots := map[string]*graphql.Object{
+		"query":        schema.QueryType(),
+		"mutation":     schema.MutationType(),
+		"subscription": schema.SubscriptionType(),
+	}
+	for _, op := range d.OperationTypes {
+		root := roots[op.Operation]
+		if root == nil {
+			b.bindFail(op.Type.Name.Value, "", "%s root is declared in SDL but missing in Go", op.Operation)
+			continue
+		}
+		if root.Name() != op.Type.Name.Value {
+			b.bindFail(root.Name(), "", "%s root is %s in SDL", op.Operation, op.Type.Name.Value)
+		}
+	}
+}
+
+func (b *Builder) bindType(typeMap graphql.TypeMap, name string, kind string) graphql.Type {
+	t, ok := typeMap[name]
+	if !ok {
+		b.bindFail(name, "", "%s %s is declared in SDL but missing in Go", kind, name)
+		return nil
+	}
+	var matches bool
+	switch kind {
+	case "scalar":
+		_, matches = t.(*graphql.Scalar)
+	case "type":
+		_, matches = t.(*graphql.Object)
+	case "interface":
+		_, matches = t.(*graphql.Interface)
+	case "union":
+		_, matches = t.(*graphql.Union)
+	case "enum":
+		_, matches = t.(*graphql.Enum)
+	case "input":
+		_, matches = t.(*graphql.InputObject)
+	}
+	if !matches {
+		b.bindFail(name, "", "%s is declared as %s in SDL but built as %s in Go", name, kind, kindOf(t))
+		return nil
+	}
+	return t
+}
+
+func (b *Builder) bindInterfaces(t *graphql.Object, declared []*ast.Named) {
+	built := make([]string, 0)
+	for _, iface := range t.Interfaces() {
+		built = append(built, iface.Name())
+	}
+	names := make([]string, 0, len(declared))
+	for _, n := range declared {
+		names = append(names, n.Name.Value)
+	}
+	b.bindNames(t.Name(), "", "interface", names, built)
+}
+
+func (b *Builder) bindUnion(t *graphql.Union, declared []*ast.Named) {
+	built := make([]string, 0)
+	for _, o := range t.Types() {
+		built = append(built, o.Name())
+	}
+	names := make([]string, 0, len(declared))
+	for _, n := range declared {
+		names = append(names, n.Name.Value)
+	}
+	b.bindNames(t.Name(), "", "member", names, built)
+}
+
+func (b *Builder) bindEnum(t *graphql.Enum, declared []*ast.EnumValueDefinition) {
+	built := make([]string, 0)
+	for _, v := range t.Values() {
+		built = append(built, v.Name)
+	}
+	names := make([]string, 0, len(declared))
+	for _, v := range declared {
+		names = append(names, v.Name.Value)
+	}
+	b.bindNames(t.Name(), "", "value", names, built)
+}
+
+func (b *Builder) bindFields(typeName string, built graphql.FieldDefinitionMap, declared []*ast.FieldDefinition) {
+	l := b.layouts[typeName]
+	names := make([]string, 0, len(built))
+	for name := range built {
+		names = append(names, name)
+	}
+	seen := make(map[string]bool)
+	for _, d := range declared {
+		name := d.Name.Value
+		seen[name] = true
+		f, ok := built[name]
+		if !ok {
+			b.bindFail(typeName, name, "field %s is declared in SDL but has neither a Go field nor a resolver Resolve%s", name, strcase.ToCamel(name))
+			continue
+		}
+		if sdlType := printAST(d.Type); sdlType != f.Type.String() {
+			b.bindFail(typeName, name, "type is %s in SDL but %s in Go", sdlType, f.Type.String())
+		}
+		// the fields of the roots resolve without a root value and arguments
+		// only reach a resolver, so a Go field cannot back them
+		if l != nil && !l.resolvers[name] && (len(d.Arguments) > 0 || rootTypes[typeName]) {
+			b.bindFail(typeName, name, "missing resolver Resolve%s", l.goNames[name])
+		}
+		args := make(map[string]*graphql.Argument)
+		argNames := make([]string, 0, len(f.Args))
+		for _, arg := range f.Args {
+			args[arg.Name()] = arg
+			argNames = append(argNames, arg.Name())
+		}
+		declaredArgs := make([]string, 0, len(d.Arguments))
+		for _, arg := range d.Arguments {
+			declaredArgs = append(declaredArgs, arg.Name.Value)
+			if a, ok := args[arg.Name.Value]; ok {
+				if sdlType := printAST(arg.Type); sdlType != a.Type.String() {
+					b.bindFail(typeName, name, "argument %s is %s in SDL but %s in Go", arg.Name.Value, sdlType, a.Type.String())
+				}
+			}
+		}
+		b.bindNames(typeName, name, "argument", declaredArgs, argNames)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !seen[name] {
+			b.bindFail(typeName, name, "field %s exists in Go but is not declared in SDL", name)
+		}
+	}
+}
+
+func (b *Builder) bindInputFields(t *graphql.InputObject, declared []*ast.InputValueDefinition) {
+	built := t.Fields()
+	names := make([]string, 0, len(built))
+	for name := range built {
+		names = append(names, name)
+	}
+	declaredNames := make([]string, 0, len(declared))
+	for _, d := range declared {
+		declaredNames = append(declaredNames, d.Name.Value)
+		if f, ok := built[d.Name.Value]; ok {
+			if sdlType := printAST(d.Type); sdlType != f.Type.String() {
+				b.bindFail(t.Name(), d.Name.Value, "type is %s in SDL but %s in Go", sdlType, f.Type.String())
+			}
+		}
+	}
+	b.bindNames(t.Name(), "", "field", declaredNames, names)
+}
+
+// bindNames - reports the names that exist on one side only
+func (b *Builder) bindNames(typeName string, field string, what string, declared []string, built []string) {
+	inGo := make(map[string]bool, len(built))
+	for _, name := range built {
+		inGo[name] = true
+	}
+	inSDL := make(map[string]bool, len(declared))
+	for _, name := range declared {
+		inSDL[name] = true
+		if !inGo[name] {
+			b.bindFail(typeName, field, "%s %s is declared in SDL but missing in Go", what, name)
+		}
+	}
+	sort.Strings(built)
+	for _, name := range built {
+		if !inSDL[name] {
+			b.bindFail(typeName, field, "%s %s exists in Go but is not declared in SDL", what, name)
+		}
+	}
+}
+
+// bindFail - reports a binding problem on the Go type and field a GraphQL
+// type and field were built from, when they are known
+func (b *Builder) bindFail(typeName string, field string, format string, args ...interface{}) {
+	l, ok := b.layouts[typeName]
+	if !ok {
+		b.errors = append(b.errors, &FieldError{
+			Type:  typeName,
+			Field: field,
+			Rule:  fmt.Sprintf(format, args...),
+		})
+		return
+	}
+	if goName, ok := l.goNames[field]; ok {
+		field = goName
+	}
+	b.fail(l.source, field, format, args...)
+}
+
+func printAST(t ast.Type) string {
+	switch t := t.(type) {
+	case *ast.NonNull:
+		return printAST(t.Type) + "!"
+	case *ast.List:
+		return "[" + printAST(t.Type) + "]"
+	case *ast.Named:
+		return t.Name.Value
+	}
+	return ""
+}
+
+func kindOf(t graphql.Type) string {
+	switch t.(type) {
+	case *graphql.Scalar:
+		return "scalar"
+	case *graphql.Object:
+		return "type"
+	case *graphql.Interface:
+		return "interface"
+	case *graphql.Union:
+		return "union"
+	case *graphql.Enum:
+		return "enum"
+	case *graphql.InputObject:
+		return "input"
+	}
+	return t.String()
+}
@@ -1,58 +1,184 @@
 package builder
 
 import (
-	"fmt"
+	"context"
 	"reflect"
 	"strings"
 
+	"github.com/cipriantarta/gogql/pkg/tags"
 	"github.com/cipriantarta/gogql/pkg/types"
 	"github.com/graphql-go/graphql"
-	"github.com/iancoleman/strcase"
-	"github.com/mitchellh/mapstructure"
 )
 
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	contextType       = reflect.TypeOf((*context.Context)(nil)).Elem()
+	resolveParamsType = reflect.TypeOf(graphql.ResolveParams{})
+)
+
+var fieldOptions = map[string]tags.Kind{
+	"inputonly":     tags.Flag,
+	"readonly":      tags.Flag,
+	"required":      tags.Flag,
+	"elem_required": tags.Flag,
+	"interface":     tags.Flag,
+	"nested":        tags.Flag,
+	"entries":       tags.Flag,
+	"-":             tags.Flag,
+	"alias":         tags.Value,
+	"description":   tags.Value,
+	"deprecated":    tags.Value,
+	"default":       tags.Literal,
+}
+
+var relayOptions = map[string]tags.Kind{
+	"key":    tags.Value,
+	"method": tags.Value,
+}
+
 type nodeType struct {
-	source       reflect.Value
-	inputOnly    bool
-	readOnly     bool
-	required     bool
-	skip         bool
-	name         string
-	alias        string
-	description  string
-	resolver     graphql.FieldResolveFn
-	resolverArgs graphql.FieldConfigArgument
-	isRelay      bool
-	relay        *relayInfo
+	source        reflect.Value
+	inputOnly     bool
+	readOnly      bool
+	required      bool
+	elemRequired  bool
+	skip          bool
+	name          string
+	alias         string
+	description   string
+	deprecated    string
+	resolver      graphql.FieldResolveFn
+	getter        graphql.FieldResolveFn
+	resolverArgs  graphql.FieldConfigArgument
+	resolverOrder []string
+	argDeprecated map[string]string
+	index         []int
+	isInterface   bool
+	nested        bool
+	entries       bool
+	hasDefault    bool
+	defaultValue  string
+	isRelay       bool
+	relay         *relayInfo
+	resolved      reflect.Type
 }
 
-// Builder GraphQL schema builder
+// resultType - the Go type a field resolves to: the first return value of its
+// Resolve method, if any, or else the type of the field
+func (n *nodeType) resultType() reflect.Type {
+	if n.resolved != nil {
+		return n.resolved
+	}
+	return n.source.Type()
+}
+
+// Builder GraphQL schema builder. A builder is not safe for concurrent use,
+// but builders share no mutable state, each owning a copy of the built-in
+// scalars and its own relay interfaces, so several schemas may be built
+// concurrently within a process, each by its own builder
 type Builder struct {
 	scalars         map[string]*graphql.Scalar
+	typeScalars     map[reflect.Type]*graphql.Scalar
+	specifiedBy     map[string]string
 	queryTypes      map[string]graphql.Output
 	mutationTypes   map[string]graphql.Input
 	interfaces      map[string]*graphql.Interface
 	enums           map[string]*graphql.Enum
+	objects         map[objectKey]graphql.Output
+	inputs          map[objectKey]graphql.Input
+	inputConfigs    map[*graphql.InputObject]graphql.InputObjectConfigFieldMap
+	builtEnums      map[reflect.Type]*graphql.Enum
+	unions          map[reflect.Type]*graphql.Union
+	entries         map[string]graphql.Type
+	names           map[string]reflect.Type
+	unionMembers    map[reflect.Type][]reflect.Type
+	implementations map[reflect.Type][]reflect.Type
+	structIfaces    map[reflect.Type]*graphql.Interface
+	inputPaths      map[reflect.Type]map[string][]int
+	layouts         map[string]*layout
+	errors          []*FieldError
+	warned          map[string]bool
+	schema          *graphql.Schema
+	defaults        []func()
+	operation       string
 	PaginationLimit int
+	// Naming - names of the fields, arguments, enum values and generated types
+	Naming NamingStrategy
+	// InferNullability - output fields and list elements whose Go type cannot
+	// hold nil, such as strings, ints and structs, are non-null. Pointers,
+	// slices, maps and interfaces stay nullable unless tagged `required`. The
+	// Go type of a field with a Resolve method is the one the method returns
+	InferNullability bool
+	// Integers - the scalar each Go integer kind maps to, graphql.Int unless
+	// set. Values which do not fit their scalar are errors, both in results
+	// and in arguments
+	Integers map[reflect.Kind]IntMapping
+	// Base64URL - byte slices map to the URL safe Base64URL scalar instead of Base64
+	Base64URL bool
+	// Warnf - when set, receives a warning for every deprecated field, argument
+	// and enum value found while building the schema
+	Warnf func(format string, args ...interface{})
 }
 
 // New builder
 func New() *Builder {
+	typeScalars := make(map[reflect.Type]*graphql.Scalar, len(defaultScalars))
+	for t, s := range defaultScalars {
+		typeScalars[t] = s
+	}
 	return &Builder{
-		scalars:         scalars,
+		scalars:         make(map[string]*graphql.Scalar),
+		typeScalars:     typeScalars,
+		specifiedBy:     make(map[string]string),
 		interfaces:      make(map[string]*graphql.Interface),
 		queryTypes:      make(map[string]graphql.Output),
 		mutationTypes:   make(map[string]graphql.Input),
 		enums:           make(map[string]*graphql.Enum),
+		objects:         make(map[objectKey]graphql.Output),
+		inputs:          make(map[objectKey]graphql.Input),
+		inputConfigs:    make(map[*graphql.InputObject]graphql.InputObjectConfigFieldMap),
+		builtEnums:      make(map[reflect.Type]*graphql.Enum),
+		unions:          make(map[reflect.Type]*graphql.Union),
+		entries:         make(map[string]graphql.Type),
+		names:           make(map[string]reflect.Type),
+		unionMembers:    make(map[reflect.Type][]reflect.Type),
+		implementations: make(map[reflect.Type][]reflect.Type),
+		structIfaces:    make(map[reflect.Type]*graphql.Interface),
+		inputPaths:      make(map[reflect.Type]map[string][]int),
+		layouts:         make(map[string]*layout),
+		warned:          make(map[string]bool),
 		PaginationLimit: 100,
+		Naming:          DefaultNaming{},
+		Integers:        make(map[reflect.Kind]IntMapping),
 	}
 }
 
-// Scalar - Add/Replace an existing scalar type with a custom one
+// Scalar - Add/Replace an existing scalar type with a custom one. Types are
+// registered by their Go type name, either bare, e.g. `Time`, or package
+// qualified, e.g. `github.com/acme/billing.Money`, which wins over the bare one.
+// The same goes for objects, enums and inputs
 func (b *Builder) Scalar(name string, value *graphql.Scalar) {
 	b.scalars[name] = value
 }
 
+// TypeScalar - maps a Go type, given as a value of it, to a scalar. Unlike
+// Scalar it tells apart types of the same name and also applies to unnamed
+// types such as `[16]byte`
+func (b *Builder) TypeScalar(value interface{}, scalar *graphql.Scalar) {
+	t := reflect.TypeOf(value)
+	if t == nil {
+		b.fail(t, "", "expected a value of the Go type of scalar %s", scalar.Name())
+		return
+	}
+	b.typeScalars[t] = scalar
+}
+
+// SpecifiedBy - sets the URL of the specification of a scalar, printed in the
+// SDL as `@specifiedBy(url: "...")`
+func (b *Builder) SpecifiedBy(scalar string, url string) {
+	b.specifiedBy[scalar] = url
+}
+
 // Object - Add/Replace an existing object type with a custom one
 func (b *Builder) Object(name string, value graphql.Output) {
 	b.queryTypes[name] = value
@@ -63,13 +189,103 @@ func (b *Builder) Enum(name string, value *graphql.Enum) {
 	b.enums[name] = value
 }
 
+// Interface - Add/Replace an existing interface type with a custom one
+func (b *Builder) Interface(name string, value *graphql.Interface) {
+	b.interfaces[name] = value
+}
+
+// Input - Add/Replace an existing input type with a custom one
+func (b *Builder) Input(name string, value graphql.Input) {
+	b.mutationTypes[name] = value
+}
+
+// Types - all the named types known to the builder, so that types which are
+// not reachable from the root objects still end up in the schema
+func (b *Builder) Types() []graphql.Type {
+	result := make([]graphql.Type, 0)
+	for _, v := range b.enums {
+		result = append(result, v)
+	}
+	for _, v := range b.builtEnums {
+		result = append(result, v)
+	}
+	for _, v := range b.interfaces {
+		result = append(result, v)
+	}
+	for _, v := range b.structIfaces {
+		result = append(result, v)
+	}
+	for _, v := range b.unions {
+		result = append(result, v)
+	}
+	for _, v := range b.queryTypes {
+		result = append(result, v)
+	}
+	for _, v := range b.objects {
+		result = append(result, v)
+	}
+	for _, v := range b.mutationTypes {
+		result = append(result, v)
+	}
+	for _, v := range b.inputs {
+		result = append(result, v)
+	}
+	for _, v := range b.entries {
+		result = append(result, v)
+	}
+	return result
+}
+
+// Schema - builds the graphql schema for the given root objects. Mutation and
+// subscription are optional. Every problem found on the way is reported at
+// once as a *SchemaError
+func (b *Builder) Schema(query interface{}, mutation interface{}, subscription interface{}) (*graphql.Schema, error) {
+	config := graphql.SchemaConfig{
+		Query: b.root("Query", query),
+	}
+	if mutation != nil {
+		config.Mutation = b.root("Mutation", mutation)
+	}
+	if subscription != nil {
+		config.Subscription = b.root("Subscription", subscription)
+	}
+	b.coerceDefaults()
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+	config.Types = b.Types()
+
+	s, err := graphql.NewSchema(config)
+	if err != nil {
+		return nil, err
+	}
+	b.schema = &s
+	return &s, nil
+}
+
+func (b *Builder) root(name string, source interface{}) *graphql.Object {
+	fields, l := b.queryFields(reflect.ValueOf(source), reflect.Value{})
+	b.layouts[name] = l
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:   name,
+		Fields: fields,
+	})
+}
+
 // QueryFields - builds the query fields for a graphql object
 func (b *Builder) QueryFields(source reflect.Value, parent reflect.Value) (graphql.Fields, error) {
+	result, _ := b.queryFields(source, parent)
+	b.coerceDefaults()
+	return result, b.Err()
+}
+
+func (b *Builder) queryFields(source reflect.Value, parent reflect.Value) (graphql.Fields, *layout) {
 	result := make(graphql.Fields, 0)
+	l := newLayout(source.Type())
 	if source.IsValid() && source.IsZero() {
 		source = reflect.New(source.Type())
 	}
-	nodes := b.buildObject(source, parent)
+	nodes := b.buildObject(source, parent, true)
 	for _, node := range nodes {
 		if node.skip {
 			continue
@@ -82,37 +298,66 @@ func (b *Builder) QueryFields(source reflect.Value, parent reflect.Value) (graph
 		}
 		name := node.alias
 		if name == "" {
-			name = strcase.ToLowerCamel(node.name)
+			name = b.naming(source.Type()).FieldName(node.name)
+		}
+		if _, ok := result[name]; ok {
+			b.fail(source.Type(), node.name, "field %s is already declared", name)
+			continue
 		}
 		var gType graphql.Type
 		if node.isRelay {
-			gType = b.buildConnection(node.source, parent)
+			gType = b.buildConnection(source.Type(), node, parent)
+		} else if node.entries {
+			gType = b.mapEntries(source.Type(), node, false)
 		} else {
 			gType = b.mapOutput(node.source, parent)
 		}
 		if gType == nil {
+			if !node.isRelay && !node.entries {
+				b.fail(source.Type(), node.name, noOutputType, node.source.Type())
+			}
 			continue
 		}
-		if node.required {
-			gType = graphql.NewNonNull(gType)
-		}
+		gType = b.modifiers(source.Type(), node, gType, true)
 
+		resolve := node.resolver
+		if resolve == nil {
+			resolve = node.getter
+		}
+		if node.entries {
+			resolve = entriesResolver(resolve)
+		} else if !node.isRelay {
+			resolve = checkRange(node.source.Type(), gType, resolve)
+			resolve = basicResolver(node.source.Type(), gType, resolve)
+		}
 		field := &graphql.Field{
-			Name:        name,
-			Type:        gType,
-			Description: node.description,
-			Resolve:     node.resolver,
-			Args:        node.resolverArgs,
+			Name:              name,
+			Type:              gType,
+			Description:       node.description,
+			DeprecationReason: node.deprecated,
+			Resolve:           resolve,
+			Args:              node.resolverArgs,
+		}
+		if node.deprecated != "" {
+			b.warn(source.Type(), node.name, "deprecated: %s", node.deprecated)
 		}
 		result[name] = field
+		l.field(name, node)
 	}
-	return result, nil
+	return result, l
 }
 
 // InputFields - build the GraphQL fields for an input object
 func (b *Builder) InputFields(source reflect.Value, parent reflect.Value) graphql.InputObjectConfigFieldMap {
+	result, _ := b.inputFields(source, parent)
+	return result
+}
+
+func (b *Builder) inputFields(source reflect.Value, parent reflect.Value) (graphql.InputObjectConfigFieldMap, *layout) {
 	result := make(graphql.InputObjectConfigFieldMap, 0)
-	nodes := b.buildObject(source, parent)
+	paths := make(map[string][]int)
+	l := newLayout(source.Type())
+	nodes := b.buildObject(source, parent, false)
 	for _, node := range nodes {
 		if node.skip {
 			continue
@@ -126,63 +371,137 @@ func (b *Builder) InputFields(source reflect.Value, parent reflect.Value) graphq
 
 		name := node.alias
 		if name == "" {
-			name = strcase.ToLowerCamel(node.name)
+			name = b.naming(source.Type()).FieldName(node.name)
 		}
-		gType := b.mapInput(node.source, parent)
-		if node.required {
-			gType = graphql.NewNonNull(gType)
+		if _, ok := result[name]; ok {
+			b.fail(source.Type(), node.name, "field %s is already declared", name)
+			continue
 		}
+		if f := unexportedEmbed(reflect.Indirect(source).Type(), node.index); f != nil && f.Type.Kind() == reflect.Ptr {
+			b.fail(source.Type(), node.name, noEmbeddedPointer, f.Name)
+			continue
+		}
+		gType := b.inputType(source.Type(), node, parent)
+		if gType == nil {
+			b.fail(source.Type(), node.name, noInputType, node.source.Type())
+			continue
+		}
+		gType = b.modifiers(source.Type(), node, gType, false).(graphql.Input)
 
 		field := &graphql.InputObjectFieldConfig{
-			Type: gType,
+			Type:        gType,
+			Description: node.description,
+		}
+		if node.deprecated != "" {
+			if node.required {
+				b.fail(source.Type(), node.name, "required input fields cannot be deprecated")
+			}
+			b.warn(source.Type(), node.name, "deprecated: %s", node.deprecated)
+		}
+		if node.hasDefault {
+			b.setDefault(source.Type(), node, gType, func(v interface{}) {
+				field.DefaultValue = v
+			})
 		}
 		result[name] = field
+		paths[name] = node.index
+		l.field(name, node)
 	}
-	return result
+	b.inputPaths[reflect.Indirect(source).Type()] = paths
+	return result, l
 }
 
-func (b *Builder) buildObject(source reflect.Value, parent reflect.Value) []*nodeType {
-	nodes := make([]*nodeType, 0)
+// buildObject - the nodes of a struct, with its computed fields on output
+func (b *Builder) buildObject(source reflect.Value, parent reflect.Value, output bool) []*nodeType {
 	if source.Kind() == reflect.Ptr {
-		return b.buildObject(source.Elem(), source)
+		return b.buildObject(source.Elem(), source, output)
 	}
 	if source.Kind() != reflect.Struct {
-		err := "Expected a struct for %s:%s. Perhaps a custom scalar was intended?"
-		panic(fmt.Sprintf(err, source.Type().Name(), source.Kind().String()))
+		b.fail(source.Type(), "", "expected a struct, got %s", source.Kind())
+		return make([]*nodeType, 0)
 	}
+	owner := parent
+	if !owner.IsValid() {
+		owner = source
+	}
+	fields := b.structNodes(source, owner, nil)
+	nodes := promote(fields)
+	if !output {
+		return nodes
+	}
+	return append(nodes, b.computedNodes(owner, fields)...)
+}
 
+// computedNodes - fields declared only by a Resolve method, such as
+// `ResolveFullName` on a struct without a FullName field. Their type is the
+// first return value of the method and they are never part of an input.
+// Methods promoted from embedded fields and those resolving a struct field,
+// exported or not, declare no field
+func (b *Builder) computedNodes(owner reflect.Value, fields []*nodeType) []*nodeType {
+	declared := make(map[string]bool, len(fields))
+	for _, node := range fields {
+		declared[strings.Title(node.name)] = true
+	}
+	result := make([]*nodeType, 0)
+	t := owner.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		name := strings.TrimPrefix(method.Name, "Resolve")
+		if name == method.Name || name == "" || declared[name] || promotedMethod(t, method.Name) {
+			continue
+		}
+		node := &nodeType{
+			name:     name,
+			readOnly: true,
+		}
+		node.resolver, node.resolverArgs, node.resolverOrder, node.argDeprecated = b.resolver(owner, name, false, nil)
+		if node.resolver == nil {
+			continue
+		}
+		node.source = reflect.New(method.Type.Out(0)).Elem()
+		result = append(result, node)
+	}
+	return result
+}
+
+// structNodes - the nodes of a struct. Resolve methods are looked up on the
+// owner, which is the outermost struct when fields are promoted from an
+// embedded struct
+func (b *Builder) structNodes(source reflect.Value, owner reflect.Value, index []int) []*nodeType {
+	nodes := make([]*nodeType, 0)
 	for i := 0; i < source.NumField(); i++ {
 		fv := source.Field(i)
 		ft := source.Type().Field(i)
 		node := &nodeType{
 			source: fv,
 			name:   ft.Name,
+			index:  append(append([]int{}, index...), i),
 		}
 		if tag, ok := ft.Tag.Lookup("graphql"); ok {
-			for _, v := range strings.Split(tag, ",") {
-				switch v {
-				case "inputonly":
-					node.inputOnly = true
-				case "readonly":
-					node.readOnly = true
-				case "required":
-					node.required = true
-				case "-":
-					node.skip = true
-				}
-				if strings.HasPrefix(v, "alias") {
-					alias := strings.TrimPrefix(v, "alias=")
-					if alias != v {
-						node.alias = alias
-					}
-				}
-				if strings.HasPrefix(v, "description") {
-					d := strings.TrimPrefix(v, "description=")
-					if d != v {
-						node.description = strings.Trim(d, "\"")
-					}
-				}
+			options, err := tags.Parse(tag, fieldOptions)
+			if err != nil {
+				b.fail(source.Type(), ft.Name, "invalid graphql tag: %v", err)
 			}
+			node.inputOnly = options.Has("inputonly")
+			node.readOnly = options.Has("readonly")
+			node.required = options.Has("required")
+			node.elemRequired = options.Has("elem_required")
+			node.isInterface = options.Has("interface")
+			node.nested = options.Has("nested")
+			node.entries = options.Has("entries")
+			node.skip = options.Has("-")
+			node.alias = options.Get("alias")
+			node.description = options.Get("description")
+			node.deprecated = options.Get("deprecated")
+			node.defaultValue = options.Get("default")
+			node.hasDefault = options.Has("default")
+		}
+		if el := b.promoted(ft); el != nil {
+			nodes = append(nodes, b.structNodes(reflect.New(el).Elem(), owner, node.index)...)
+			continue
+		}
+		if node.isInterface && embedded(ft) == nil {
+			b.fail(source.Type(), ft.Name, "only embedded structs can be interfaces")
 		}
 		if tag, ok := ft.Tag.Lookup("relay"); ok {
 			node.isRelay = true
@@ -190,61 +509,74 @@ func (b *Builder) buildObject(source reflect.Value, parent reflect.Value) []*nod
 				key:    "ID",
 				method: "String",
 			}
-			for _, v := range strings.Split(tag, ",") {
-				relay := strings.Split(v, "=")
-				if len(relay) != 2 {
-					continue
-				}
-				switch relay[0] {
-				case "key":
-					node.relay.key = relay[1]
-				case "method":
-					node.relay.method = relay[1]
-				}
+			options, err := tags.Parse(tag, relayOptions)
+			if err != nil {
+				b.fail(source.Type(), ft.Name, "invalid relay tag: %v", err)
+			}
+			if options.Has("key") {
+				node.relay.key = options.Get("key")
+			}
+			if options.Has("method") {
+				node.relay.method = options.Get("method")
 			}
 		}
-		if parent.IsValid() {
-			node.resolver, node.resolverArgs = b.resolver(parent, ft.Name, node.isRelay, node.relay)
-		} else {
-			node.resolver, node.resolverArgs = b.resolver(source, ft.Name, node.isRelay, node.relay)
+		node.resolver, node.resolverArgs, node.resolverOrder, node.argDeprecated = b.resolver(owner, ft.Name, node.isRelay, node.relay)
+		if node.resolver != nil {
+			node.resolved = owner.MethodByName("Resolve" + strings.Title(ft.Name)).Type().Out(0)
+		}
+		if owner.IsValid() {
+			node.getter = fieldResolver(owner.Type(), node.index)
 		}
 		nodes = append(nodes, node)
 	}
 	return nodes
 }
 
-func (b *Builder) resolver(source reflect.Value, fieldName string, isRelay bool, relay *relayInfo) (graphql.FieldResolveFn, graphql.FieldConfigArgument) {
+func (b *Builder) resolver(source reflect.Value, fieldName string, isRelay bool, relay *relayInfo) (graphql.FieldResolveFn, graphql.FieldConfigArgument, []string, map[string]string) {
 	if !source.IsValid() {
-		return nil, nil
+		return nil, nil, nil, nil
 	}
 
 	name := "Resolve" + strings.Title(fieldName)
 	method := source.MethodByName(name)
 	if !method.IsValid() {
-		return nil, nil
+		return nil, nil, nil, nil
 	}
 	methodType := method.Type()
 	nIn := methodType.NumIn()
 	nOut := methodType.NumOut()
+	// a trailing types.FieldMask is filled in after the other parameters
+	nArgs := nIn
+	hasMask := nIn > 1 && methodType.In(nIn-1) == fieldMaskType
+	if hasMask {
+		nArgs--
+	}
 	if nOut != 2 {
-		panic(fmt.Sprintf("%s expected two output params. Got %d", name, nOut))
+		b.fail(source.Type(), name, "expected two output params, got %d", nOut)
+		return nil, nil, nil, nil
 	}
-	if !methodType.Out(1).Implements(reflect.TypeOf((*error)(nil)).Elem()) {
-		panic(fmt.Sprintf("%s second output parameter must be of type error", name))
+	if !methodType.Out(1).Implements(errorType) {
+		b.fail(source.Type(), name, "second output parameter must be of type error")
+		return nil, nil, nil, nil
+	}
+	if isRelay && methodType.Out(0).Kind() != reflect.Slice {
+		b.fail(source.Type(), name, "first output parameter must be a slice when using relay")
+		return nil, nil, nil, nil
 	}
 
-	in := make([]reflect.Value, nIn)
 	args := make(graphql.FieldConfigArgument)
-	if nIn > 0 {
-		p := methodType.In(0)
-		if p.Kind() == reflect.Ptr {
-			p = p.Elem()
-		}
-		if p != reflect.TypeOf(graphql.ResolveParams{}) {
-			panic(fmt.Sprintf("First argument to %s must be `ResolveParams`", name))
+	order := make([]string, 0)
+	deprecated := make(map[string]string)
+	valid := true
+	if nArgs > 0 {
+		switch methodType.In(0) {
+		case resolveParamsType, reflect.PtrTo(resolveParamsType), contextType:
+		default:
+			b.fail(source.Type(), name, "first argument must be `ResolveParams` or `context.Context`")
+			valid = false
 		}
 	}
-	if nIn > 1 {
+	if nArgs > 1 {
 		p := methodType.In(1)
 		if p.Kind() == reflect.Ptr {
 			p = p.Elem()
@@ -252,68 +584,96 @@ func (b *Builder) resolver(source reflect.Value, fieldName string, isRelay bool,
 		if isRelay {
 			relayArgs := reflect.TypeOf(types.PageArguments{})
 			if p != relayArgs {
-				panic(fmt.Sprintf("Second argument to %s must be `PageArguments`", name))
+				b.fail(source.Type(), name, "second argument must be `PageArguments`")
+				valid = false
+			} else {
+				order = append(order, b.arguments(relayArgs, args, deprecated, source.Type(), name)...)
 			}
-			b.arguments(reflect.TypeOf(types.PageArguments{}), args, name)
 		} else {
 			if p.Kind() != reflect.Struct {
-				panic(fmt.Sprintf("Second argument to %s must be a struct", name))
+				b.fail(source.Type(), name, "second argument must be a struct")
+				valid = false
+			} else {
+				order = append(order, b.arguments(p, args, deprecated, source.Type(), name)...)
 			}
-			b.arguments(p, args, name)
 		}
 	}
-	if nIn > 2 {
+	if nArgs > 2 {
 		if !isRelay {
-			panic(fmt.Sprintf("%s must have maximum 2 arguments when not using relay", name))
+			b.fail(source.Type(), name, "must have maximum 2 arguments when not using relay")
+			return nil, nil, nil, nil
 		}
-		if nIn > 3 {
-			panic(fmt.Sprintf("%s must have maximum 3 arguments when using relay", name))
+		if nArgs > 3 {
+			b.fail(source.Type(), name, "must have maximum 3 arguments when using relay")
+			return nil, nil, nil, nil
 		}
 		p := methodType.In(2)
 		if p.Kind() == reflect.Ptr {
 			p = p.Elem()
 		}
 		if p.Kind() != reflect.Struct {
-			panic(fmt.Sprintf("Third argument to %s must be a struct", name))
+			b.fail(source.Type(), name, "third argument must be a struct")
+			valid = false
+		} else {
+			order = append(order, b.arguments(p, args, deprecated, source.Type(), name)...)
 		}
-		b.arguments(p, args, name)
+	}
+	if !valid {
+		return nil, nil, nil, nil
 	}
 	m := func(p graphql.ResolveParams) (interface{}, error) {
-		var pageArgs *types.PageArguments
-		v := reflect.ValueOf(p.Source)
-		if v.IsValid() {
-			m := v.MethodByName(name)
-			if m.IsValid() {
-				method = m
+		ctx := p.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		call := method
+		if v := reflect.ValueOf(p.Source); v.IsValid() {
+			if m := v.MethodByName(name); m.IsValid() {
+				call = m
 			}
 		}
-		if nIn > 0 {
-			in[0] = reflect.ValueOf(p)
+
+		var pageArgs *types.PageArguments
+		in := make([]reflect.Value, nIn)
+		if nArgs > 0 {
+			switch methodType.In(0) {
+			case contextType:
+				in[0] = reflect.ValueOf(&ctx).Elem()
+			case resolveParamsType:
+				in[0] = reflect.ValueOf(p)
+			default:
+				in[0] = reflect.ValueOf(&p)
+			}
 		}
-		if nIn > 1 {
+		if nArgs > 1 {
 			if isRelay {
 				pageArgs = &types.PageArguments{Limit: b.PaginationLimit}
-				if err := mapstructure.Decode(p.Args, pageArgs); err != nil {
-					panic(err)
+				if err := b.decode(p.Args, pageArgs); err != nil {
+					return nil, err
 				}
 				in[1] = reflect.ValueOf(pageArgs)
 			} else {
-				arg := reflect.Zero(methodType.In(1)).Interface()
-				if err := mapstructure.Decode(p.Args, &arg); err != nil {
-					panic(err)
+				arg, err := b.argument(p.Args, methodType.In(1))
+				if err != nil {
+					return nil, err
 				}
-				in[1] = reflect.ValueOf(arg)
+				in[1] = arg
 			}
-
 		}
-		if nIn > 2 {
-			arg := reflect.Zero(methodType.In(2)).Interface()
-			if err := mapstructure.Decode(p.Args, &arg); err != nil {
-				panic(err)
+		if nArgs > 2 {
+			arg, err := b.argument(p.Args, methodType.In(2))
+			if err != nil {
+				return nil, err
 			}
-			in[2] = reflect.ValueOf(arg)
+			in[2] = arg
 		}
-		r := method.Call(in)
+		if hasMask {
+			in[nIn-1] = reflect.ValueOf(fieldMask(p))
+		}
+		r := call.Call(in)
 		var err error = nil
 		if e, ok := r[1].Interface().(error); ok {
 			err = e
@@ -323,65 +683,111 @@ func (b *Builder) resolver(source reflect.Value, fieldName string, isRelay bool,
 		}
 		return r[0].Interface(), err
 	}
-	return m, args
+	return m, args, order, deprecated
 }
 
-func (b *Builder) arguments(t reflect.Type, args graphql.FieldConfigArgument, resolverName string) {
-	parent := reflect.Value{}
-	source := reflect.New(t).Elem()
-	valid := false
-	for i := 0; i < t.NumField(); i++ {
-		f := t.Field(i)
-		ft := source.FieldByName(f.Name)
-		if !ft.CanSet() {
-			continue
-		}
-
-		description := ""
-		name := strcase.ToLowerCamel(f.Name)
-		el := f.Type
-		if el.Kind() == reflect.Ptr {
-			el = el.Elem()
-		}
+// inputType - the input type of an input field or argument
+func (b *Builder) inputType(owner reflect.Type, node *nodeType, parent reflect.Value) graphql.Input {
+	if !node.entries {
+		return b.mapInput(node.source, parent)
+	}
+	if t := b.mapEntries(owner, node, true); t != nil {
+		return t.(graphql.Input)
+	}
+	return nil
+}
 
-		argVal := reflect.New(el).Elem()
-		v := b.mapInput(argVal, parent)
+// argument - decodes the GraphQL arguments into a resolver parameter, which is
+// either a struct or a pointer to one
+func (b *Builder) argument(args map[string]interface{}, t reflect.Type) (reflect.Value, error) {
+	el := t
+	if el.Kind() == reflect.Ptr {
+		el = el.Elem()
+	}
+	v := reflect.New(el)
+	if err := b.decode(args, v.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	if t.Kind() == reflect.Ptr {
+		return v, nil
+	}
+	return v.Elem(), nil
+}
 
-		if tag, ok := f.Tag.Lookup("graphql"); ok {
-			skip := false
-			for _, t := range strings.Split(tag, ",") {
-				switch t {
-				case "required":
-					v = graphql.NewNonNull(v)
-				case "readonly", "-":
-					skip = true
-				}
-				if strings.HasPrefix(t, "alias") {
-					alias := strings.TrimPrefix(t, "alias=")
-					if alias != t {
-						name = alias
-					}
-				}
-				if strings.HasPrefix(t, "description") {
-					d := strings.TrimPrefix(t, "description=")
-					if d != t {
-						description = strings.Trim(d, "\"")
-					}
+// fieldResolver - resolves a struct field by its index, so that aliased fields
+// and the ones promoted from embedded structs are found too
+func fieldResolver(owner reflect.Type, index []int) graphql.FieldResolveFn {
+	if owner.Kind() == reflect.Ptr {
+		owner = owner.Elem()
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		v := reflect.Indirect(reflect.ValueOf(p.Source))
+		if !v.IsValid() || v.Type() != owner {
+			return graphql.DefaultResolveFn(p)
+		}
+		for _, i := range index {
+			for v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return nil, nil
 				}
+				v = v.Elem()
 			}
-			if skip {
-				continue
-			}
+			v = v.Field(i)
+		}
+		return v.Interface(), nil
+	}
+}
+
+func (b *Builder) arguments(t reflect.Type, args graphql.FieldConfigArgument, deprecated map[string]string, owner reflect.Type, resolverName string) []string {
+	// the inputs built for the arguments are named after their field
+	defer func(operation string) {
+		b.operation = operation
+	}(b.operation)
+	b.operation = strings.TrimPrefix(resolverName, "Resolve")
+	order := make([]string, 0)
+	paths := make(map[string][]int)
+	for _, node := range promote(b.structNodes(reflect.New(t).Elem(), reflect.Value{}, nil)) {
+		if node.skip || node.readOnly || !node.source.CanSet() {
+			continue
+		}
+		name := node.alias
+		if name == "" {
+			name = b.naming(t).ArgumentName(node.name)
 		}
+		if f := unexportedEmbed(t, node.index); f != nil && f.Type.Kind() == reflect.Ptr {
+			b.fail(t, node.name, noEmbeddedPointer, f.Name)
+			continue
+		}
+		v := b.inputType(t, node, reflect.Value{})
+		if v == nil {
+			b.fail(t, node.name, noInputType, node.source.Type())
+			continue
+		}
+		v = b.modifiers(t, node, v, false).(graphql.Input)
 
 		arg := &graphql.ArgumentConfig{
 			Type:        v,
-			Description: description,
+			Description: node.description,
+		}
+		if node.hasDefault {
+			b.setDefault(t, node, v, func(value interface{}) {
+				arg.DefaultValue = value
+			})
+		}
+		if node.deprecated != "" {
+			if node.required {
+				b.fail(t, node.name, "required arguments cannot be deprecated")
+			}
+			b.warn(t, node.name, "deprecated: %s", node.deprecated)
+			deprecated[name] = node.deprecated
 		}
 		args[name] = arg
-		valid = true
+		order = append(order, name)
+		paths[name] = node.index
 	}
-	if !valid {
-		panic(fmt.Sprintf("%s last argument has no exported fields", resolverName))
+	b.inputPaths[t] = paths
+	if len(order) == 0 {
+		b.fail(owner, resolverName, "last argument has no exported fields")
 	}
+	return order
 }
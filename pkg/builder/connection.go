@@ -33,48 +33,51 @@ type Connection struct {
 	Edges    interface{}
 }
 
-func (b *Builder) buildConnection(source reflect.Value, parent reflect.Value) graphql.Output {
-	b.buildInterfaces()
-
-	if isSequence(source) {
-		source = reflect.New(source.Type().Elem()).Elem()
+// buildConnection - the connection type of a relay field, which must be a
+// slice of structs or of pointers to structs
+func (b *Builder) buildConnection(owner reflect.Type, field *nodeType, parent reflect.Value) graphql.Output {
+	t := field.source.Type()
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
 	}
-
-	var el reflect.Value
-	if isPtr(source) {
-		el = source.Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
 	}
-	if !el.IsValid() {
-		el = reflect.New(source.Type().Elem()).Elem()
+	if !isSequence(field.source) || t.Kind() != reflect.Struct {
+		b.fail(owner, field.name, "relay fields must be slices of structs or of pointers to structs, got %s", field.source.Type())
+		return nil
 	}
-	name := typeName(el.Type())
-	node := b.mapObject(el, parent, []*graphql.Interface{b.interfaces["INode"]}, name+"Node")
+	b.buildInterfaces()
+
+	el := reflect.New(t).Elem()
+	node := b.mapObject(el, parent, []*graphql.Interface{b.interfaces["INode"]}, b.Naming.NodeTypeName(el.Type()), nil)
 
-	_ = b.mapObject(reflect.ValueOf(&PageInfo{}), reflect.Value{}, []*graphql.Interface{b.interfaces["IPageInfo"]}, "")
+	_ = b.mapObject(reflect.ValueOf(&PageInfo{}), reflect.Value{}, []*graphql.Interface{b.interfaces["IPageInfo"]}, "", nil)
 
-	edge := b.mapObject(reflect.ValueOf(&Edge{}), reflect.Value{}, []*graphql.Interface{b.interfaces["IEdge"]}, name+"Edge").(*graphql.Object)
-	edge.AddFieldConfig("node", &graphql.Field{Type: graphql.NewNonNull(node)})
+	edge := b.mapObject(reflect.ValueOf(&Edge{}), reflect.Value{}, []*graphql.Interface{b.interfaces["IEdge"]}, b.Naming.EdgeTypeName(el.Type()), graphql.Fields{
+		"node": &graphql.Field{Type: graphql.NewNonNull(node)},
+	})
 	edges := graphql.NewList(edge)
 
-	connection := b.mapObject(reflect.ValueOf(&Connection{}), reflect.Value{}, []*graphql.Interface{b.interfaces["IConnection"]}, name+"Connection").(*graphql.Object)
-	connection.AddFieldConfig("edges", &graphql.Field{Type: graphql.NewNonNull(edges)})
+	connection := b.mapObject(reflect.ValueOf(&Connection{}), reflect.Value{}, []*graphql.Interface{b.interfaces["IConnection"]}, b.Naming.ConnectionTypeName(el.Type()), graphql.Fields{
+		"edges": &graphql.Field{Type: graphql.NewNonNull(edges)},
+	})
 	return graphql.NewNonNull(connection)
 }
 
 func connectionResolver(nodes interface{}, err error, relayInfo *relayInfo, pageArgs *types.PageArguments) (interface{}, error) {
 	n := reflect.ValueOf(nodes)
 	if n.Kind() != reflect.Slice {
-		panic("Connection result expects a slice")
+		return nil, fmt.Errorf("connection result expects a slice, got %s", n.Kind())
 	}
 	edges := make([]interface{}, 0)
 	pageInfo := &PageInfo{HasMore: n.Len() > pageArgs.Limit}
-	for i := 0; i < n.Len(); i++ {
-		if i >= pageArgs.Limit {
-			pageInfo.EndCursor = edges[i-1].(*Edge).Cursor
-			break
-		}
+	for i := 0; i < n.Len() && i < pageArgs.Limit; i++ {
 		node := n.Index(i)
-		cursor := node.Elem().FieldByName(relayInfo.key)
+		if node.Kind() == reflect.Ptr && node.IsNil() {
+			continue
+		}
+		cursor := reflect.Indirect(node).FieldByName(relayInfo.key)
 		if reflect.Ptr == cursor.Kind() {
 			cursor = cursor.Elem()
 		}
@@ -91,12 +94,10 @@ func connectionResolver(nodes interface{}, err error, relayInfo *relayInfo, page
 			Cursor: c,
 			Node:   node.Interface(),
 		})
-		if i == 0 {
-			pageInfo.StartCursor = c
-		}
-		if i == n.Len()-1 {
-			pageInfo.EndCursor = c
-		}
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = edges[0].(*Edge).Cursor
+		pageInfo.EndCursor = edges[len(edges)-1].(*Edge).Cursor
 	}
 	c := &Connection{
 		PageInfo: pageInfo,
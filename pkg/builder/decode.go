@@ -0,0 +1,118 @@
+package builder
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// decode - decodes the GraphQL arguments into a Go value
+func (b *Builder) decode(input interface{}, output interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(invalidValue, idValue, overflow, unmarshal, entries, b.reshape, rawJSON),
+		Result:     output,
+	})
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(input)
+}
+
+// invalidValue - returns the error a scalar parsed an invalid argument into.
+// graphql-go reports values a scalar fails to parse by a generic message, so
+// scalars may parse them into an error carrying the reason instead
+func invalidValue(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if err, ok := data.(error); ok {
+		return nil, err
+	}
+	return data, nil
+}
+
+// idValue - parses the strings graphql.ID gives arguments into integer ID types
+func idValue(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	s, ok := data.(string)
+	if !ok || !isID(to) || to.Kind() == reflect.String {
+		return data, nil
+	}
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(s, 10, 64)
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// rawJSON - encodes the value of a JSON scalar for json.RawMessage fields
+func rawJSON(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != rawMessageType || from == rawMessageType {
+		return data, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(raw), nil
+}
+
+// goFields - an input object reshaped after its Go fields, which reshape
+// leaves as is
+type goFields map[string]interface{}
+
+// reshape - renames the GraphQL keys of an input object after the Go fields
+// they were built from, nesting the fields promoted from embedded structs.
+// Fields promoted from unexported embedded structs have no name to nest them
+// under, so the object is then decoded here and they are set by their index
+func (b *Builder) reshape(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	m, ok := data.(map[string]interface{})
+	if !ok || to.Kind() != reflect.Struct {
+		return data, nil
+	}
+	paths, ok := b.inputPaths[to]
+	if !ok {
+		return data, nil
+	}
+	result := make(map[string]interface{}, len(m))
+	hidden := make(map[string]interface{})
+	for k, v := range m {
+		index, ok := paths[k]
+		if !ok {
+			result[k] = v
+			continue
+		}
+		if unexportedEmbed(to, index) != nil {
+			hidden[k] = v
+			continue
+		}
+		current := result
+		t := to
+		for _, i := range index[:len(index)-1] {
+			f := t.Field(i)
+			next, ok := current[f.Name].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				current[f.Name] = next
+			}
+			current = next
+			t = f.Type
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+		}
+		current[t.Field(index[len(index)-1]).Name] = v
+	}
+	if len(hidden) == 0 {
+		return result, nil
+	}
+	value := reflect.New(to)
+	if err := b.decode(goFields(result), value.Interface()); err != nil {
+		return nil, err
+	}
+	for k, v := range hidden {
+		field := fieldByIndex(value.Elem(), paths[k])
+		if err := b.decode(v, field.Addr().Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return value.Elem().Interface(), nil
+}
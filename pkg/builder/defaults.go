@@ -0,0 +1,148 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	gqlprinter "github.com/graphql-go/graphql/language/printer"
+)
+
+// defaultValue - parses the `default` tag literal, e.g. `20`, `ASC` or
+// `{limit: 10}`, into the internal value of the given GraphQL type
+func (b *Builder) defaultValue(literal string, t graphql.Input) (interface{}, error) {
+	value, err := parseLiteral(literal)
+	if err != nil {
+		return nil, err
+	}
+	return b.coerce(value, t)
+}
+
+// setDefault - sets the default value of an argument or input field once the
+// input objects are complete, as reading the fields of an input object while
+// it is built would cache them half empty
+func (b *Builder) setDefault(owner reflect.Type, node *nodeType, t graphql.Input, set func(interface{})) {
+	b.defaults = append(b.defaults, func() {
+		v, err := b.defaultValue(node.defaultValue, t)
+		if err != nil {
+			b.fail(owner, node.name, "invalid default value: %v", err)
+			return
+		}
+		set(v)
+	})
+}
+
+// coerceDefaults - sets the default values pending since the last call, inner
+// input objects first as their fields are built first
+func (b *Builder) coerceDefaults() {
+	defaults := b.defaults
+	b.defaults = nil
+	for _, set := range defaults {
+		set()
+	}
+}
+
+func parseLiteral(literal string) (ast.Value, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: "{f(v: " + literal + ")}"})
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a GraphQL value", literal)
+	}
+	if len(doc.Definitions) == 1 {
+		if op, ok := doc.Definitions[0].(*ast.OperationDefinition); ok && len(op.SelectionSet.Selections) == 1 {
+			if f, ok := op.SelectionSet.Selections[0].(*ast.Field); ok && len(f.Arguments) == 1 {
+				return f.Arguments[0].Value, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%q is not a GraphQL value", literal)
+}
+
+// coerce - the internal value of a literal, the way the executor would read it
+// from a query
+func (b *Builder) coerce(value ast.Value, ttype graphql.Input) (interface{}, error) {
+	if _, ok := value.(*ast.Variable); ok {
+		return nil, errors.New("variables are not allowed in default values")
+	}
+	switch t := ttype.(type) {
+	case *graphql.NonNull:
+		return b.coerce(value, t.OfType)
+	case *graphql.List:
+		list, ok := value.(*ast.ListValue)
+		if !ok {
+			v, err := b.coerce(value, t.OfType)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{v}, nil
+		}
+		result := make([]interface{}, 0, len(list.Values))
+		for _, item := range list.Values {
+			v, err := b.coerce(item, t.OfType)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, v)
+		}
+		return result, nil
+	case *graphql.InputObject:
+		obj, ok := value.(*ast.ObjectValue)
+		if !ok {
+			return nil, fmt.Errorf("expected an object for %s, got %v", t.Name(), gqlprinter.Print(value))
+		}
+		fields := b.inputFieldConfigs(t)
+		result := make(map[string]interface{})
+		for _, f := range obj.Fields {
+			field, ok := fields[f.Name.Value]
+			if !ok {
+				return nil, fmt.Errorf("%s has no field %s", t.Name(), f.Name.Value)
+			}
+			v, err := b.coerce(f.Value, field.Type)
+			if err != nil {
+				return nil, err
+			}
+			result[f.Name.Value] = v
+		}
+		for name, field := range fields {
+			if _, ok := result[name]; ok {
+				continue
+			}
+			if field.DefaultValue != nil {
+				result[name] = field.DefaultValue
+			} else if _, ok := field.Type.(*graphql.NonNull); ok {
+				return nil, fmt.Errorf("missing required field %s of %s", name, t.Name())
+			}
+		}
+		return result, nil
+	case *graphql.Scalar:
+		// scalars such as Long parse invalid values into the error
+		switch v := t.ParseLiteral(value).(type) {
+		case nil:
+		case error:
+			return nil, v
+		default:
+			return v, nil
+		}
+	case *graphql.Enum:
+		if v := t.ParseLiteral(value); v != nil {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("%v is not a valid %s", gqlprinter.Print(value), ttype.Name())
+}
+
+// inputFieldConfigs - the fields of an input object as configured. Those of
+// the input objects built here are read from their config, as Fields caches
+// the default values found on its first call
+func (b *Builder) inputFieldConfigs(t *graphql.InputObject) graphql.InputObjectConfigFieldMap {
+	if fields, ok := b.inputConfigs[t]; ok {
+		return fields
+	}
+	result := make(graphql.InputObjectConfigFieldMap)
+	for name, f := range t.Fields() {
+		result[name] = &graphql.InputObjectFieldConfig{Type: f.Type, DefaultValue: f.DefaultValue}
+	}
+	return result
+}
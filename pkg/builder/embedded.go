@@ -0,0 +1,110 @@
+package builder
+
+import "reflect"
+
+// promoted - the struct type of an embedded field whose fields are promoted to
+// the outer struct. Embedded structs tagged as `nested` or mapped to a scalar,
+// registered or generated from their marshalers, stay a field of their own
+func (b *Builder) promoted(field reflect.StructField) reflect.Type {
+	el := embedded(field)
+	if el == nil {
+		return nil
+	}
+	tag := field.Tag.Get("graphql")
+	if hasOption(tag, "-") || hasOption(tag, "nested") {
+		return nil
+	}
+	if b.mapScalar(reflect.New(el).Elem()) != nil || b.mapMarshaler(el) != nil {
+		return nil
+	}
+	return el
+}
+
+// promote - drops the fields shadowed by shallower ones and the ambiguous
+// ones, following the Go rules for promoted fields
+func promote(nodes []*nodeType) []*nodeType {
+	depth := make(map[string]int)
+	count := make(map[string]int)
+	for _, node := range nodes {
+		d, ok := depth[node.name]
+		switch {
+		case !ok || len(node.index) < d:
+			depth[node.name] = len(node.index)
+			count[node.name] = 1
+		case len(node.index) == d:
+			count[node.name]++
+		}
+	}
+	result := make([]*nodeType, 0, len(nodes))
+	for _, node := range nodes {
+		if len(node.index) == depth[node.name] && count[node.name] == 1 {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// promotedMethod - whether the method of a struct is promoted from one of its
+// embedded fields
+func promotedMethod(t reflect.Type, name string) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+		if _, ok := f.Type.MethodByName(name); ok {
+			return true
+		}
+		if f.Type.Kind() != reflect.Ptr && f.Type.Kind() != reflect.Interface {
+			if _, ok := reflect.PtrTo(f.Type).MethodByName(name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unexportedEmbed - the unexported embedded struct the field at index is
+// promoted through, if any. Such fields cannot be set by name, but can be
+// through reflection unless the embedded struct is a pointer, which is
+// returned first
+func unexportedEmbed(t reflect.Type, index []int) *reflect.StructField {
+	var result *reflect.StructField
+	for _, i := range index[:len(index)-1] {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			if f.Type.Kind() == reflect.Ptr {
+				return &f
+			}
+			if result == nil {
+				result = &f
+			}
+		}
+		t = f.Type
+	}
+	return result
+}
+
+// fieldByIndex - the field of v at index, allocating the nil embedded
+// pointers on the way
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
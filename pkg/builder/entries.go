@@ -0,0 +1,157 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+)
+
+// mapEntries - the list of key/value entries a map field tagged `entries` is
+// exposed as, e.g. `[StringIntEntry!]` for a map[string]int or
+// `[StringIntEntryInput!]` on input. Keys must be scalars or enums
+func (b *Builder) mapEntries(owner reflect.Type, node *nodeType, isInput bool) graphql.Type {
+	t := node.source.Type()
+	if t.Kind() != reflect.Map {
+		b.fail(owner, node.name, "entries is only valid on maps")
+		return nil
+	}
+	key := b.mapIntersection(reflect.New(t.Key()).Elem(), isInput)
+	switch key.(type) {
+	case *graphql.Scalar, *graphql.Enum:
+	default:
+		b.fail(owner, node.name, "entries keys must be scalars or enums, got %s", t.Key())
+		return nil
+	}
+	el := reflect.New(t.Elem())
+	var value graphql.Type
+	if isInput {
+		if in := b.mapInput(el.Elem(), el); in != nil {
+			value = in
+		}
+	} else if out := b.mapOutput(el.Elem(), el); out != nil {
+		value = out
+	}
+	if value == nil {
+		b.fail(owner, node.name, "no GraphQL type for the entries values of %s", t)
+		return nil
+	}
+
+	name := b.typeName(t) + "Entry"
+	if t.Name() == "" {
+		name = entryTypeName(key) + entryTypeName(value) + "Entry"
+	}
+	if isInput {
+		name += "Input"
+	}
+	entry, ok := b.entries[name]
+	if !ok {
+		b.claim(name, t)
+		if isInput {
+			entry = graphql.NewInputObject(graphql.InputObjectConfig{
+				Name: name,
+				Fields: graphql.InputObjectConfigFieldMap{
+					"key":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(key)},
+					"value": &graphql.InputObjectFieldConfig{Type: value},
+				},
+			})
+		} else {
+			entry = graphql.NewObject(graphql.ObjectConfig{
+				Name: name,
+				Fields: graphql.Fields{
+					"key":   &graphql.Field{Type: graphql.NewNonNull(key)},
+					"value": &graphql.Field{Type: value},
+				},
+			})
+		}
+		l := newLayout(t)
+		l.fields = []string{"key", "value"}
+		b.layouts[name] = l
+		b.entries[name] = entry
+	}
+	return graphql.NewList(graphql.NewNonNull(entry))
+}
+
+// entryTypeName - the part of the name of an entry type given by its key or
+// value type, e.g. String or StringList
+func entryTypeName(t graphql.Type) string {
+	switch t := t.(type) {
+	case *graphql.NonNull:
+		return entryTypeName(t.OfType)
+	case *graphql.List:
+		return entryTypeName(t.OfType) + "List"
+	}
+	return t.Name()
+}
+
+// entriesResolver - resolves a map field as its list of entries, sorted by key
+func entriesResolver(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		value, err := resolve(p)
+		if err != nil {
+			return value, err
+		}
+		v := reflect.ValueOf(value)
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Map || v.IsNil() {
+			return nil, nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return lessKey(keys[i], keys[j])
+		})
+		result := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			result = append(result, map[string]interface{}{
+				"key":   k.Interface(),
+				"value": v.MapIndex(k).Interface(),
+			})
+		}
+		return result, nil
+	}
+}
+
+func lessKey(a reflect.Value, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
+}
+
+// entries - converts the entries of an input list back into a map, whose
+// keys and values mapstructure then decodes into the Go map
+func entries(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	list, ok := data.([]interface{})
+	if !ok || to.Kind() != reflect.Map {
+		return data, nil
+	}
+	result := make(map[interface{}]interface{}, len(list))
+	for _, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return data, nil
+		}
+		key := entry["key"]
+		if key == nil || !reflect.TypeOf(key).Comparable() {
+			return nil, fmt.Errorf("invalid entry key %v", key)
+		}
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("duplicate entry key %v", key)
+		}
+		result[key] = entry["value"]
+	}
+	return result, nil
+}
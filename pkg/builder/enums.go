@@ -0,0 +1,69 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/cipriantarta/gogql/pkg/types"
+	"github.com/graphql-go/graphql"
+)
+
+var enumValueName = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+// buildEnum - builds a GraphQL enum for Go named types with an
+// `EnumValues() []T` method. Each value is named after its String() method
+func (b *Builder) buildEnum(source reflect.Type) *graphql.Enum {
+	if source.Kind() == reflect.Ptr || source.Name() == "" {
+		return nil
+	}
+	if e, ok := b.builtEnums[source]; ok {
+		return e
+	}
+	method, ok := source.MethodByName("EnumValues")
+	if !ok {
+		return nil
+	}
+	if method.Type.NumIn() != 1 || method.Type.NumOut() != 1 ||
+		method.Type.Out(0).Kind() != reflect.Slice || method.Type.Out(0).Elem() != source {
+		b.fail(source, "EnumValues", "expected EnumValues() []%s", source.Name())
+		return nil
+	}
+	if !source.Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem()) {
+		b.fail(source, "String", "enum values must implement fmt.Stringer")
+		return nil
+	}
+
+	name := b.typeName(source)
+	b.claim(name, source)
+	l := newLayout(source)
+	values := make(graphql.EnumValueConfigMap)
+	list := method.Func.Call([]reflect.Value{reflect.Zero(source)})[0]
+	for i := 0; i < list.Len(); i++ {
+		v := list.Index(i).Interface()
+		valueName := b.Naming.EnumValueName(v.(fmt.Stringer).String())
+		if !enumValueName.MatchString(valueName) {
+			b.fail(source, "String", "%q is not a valid enum value name", valueName)
+			continue
+		}
+		config := &graphql.EnumValueConfig{Value: v}
+		if d, ok := v.(types.EnumDescriber); ok {
+			config.Description = d.EnumDescription()
+		}
+		if d, ok := v.(types.EnumDeprecator); ok {
+			config.DeprecationReason = d.EnumDeprecationReason()
+		}
+		if config.DeprecationReason != "" {
+			b.warn(source, valueName, "deprecated: %s", config.DeprecationReason)
+		}
+		values[valueName] = config
+		l.fields = append(l.fields, valueName)
+	}
+	e := graphql.NewEnum(graphql.EnumConfig{
+		Name:   name,
+		Values: values,
+	})
+	b.builtEnums[source] = e
+	b.layouts[name] = l
+	return e
+}
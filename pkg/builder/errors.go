@@ -0,0 +1,102 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	noInputType  = "no GraphQL input type for %s. Perhaps a custom scalar was intended?"
+	noOutputType = "no GraphQL output type for %s. Perhaps a custom scalar was intended?"
+	// the input values of fields promoted through a nil unexported pointer
+	// have nowhere to go, as reflection cannot allocate it
+	noEmbeddedPointer = "cannot be set through the unexported embedded pointer %s"
+)
+
+// FieldError - a single problem found while building a schema
+type FieldError struct {
+	// Type - package qualified path of the Go type the problem was found on
+	Type string
+	// Field - Go field or method name
+	Field string
+	// Rule - the rule that was broken
+	Rule string
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", e.Type, e.Rule)
+	}
+	return fmt.Sprintf("%s.%s: %s", e.Type, e.Field, e.Rule)
+}
+
+// SchemaError - every problem found while building a schema
+type SchemaError struct {
+	Errors []*FieldError
+}
+
+func (e *SchemaError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	if len(e.Errors) == 1 {
+		lines = append(lines, "schema has 1 error:")
+	} else {
+		lines = append(lines, fmt.Sprintf("schema has %d errors:", len(e.Errors)))
+	}
+	for _, err := range e.Errors {
+		lines = append(lines, "\t"+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (b *Builder) fail(source reflect.Type, field string, format string, args ...interface{}) {
+	err := &FieldError{
+		Type:  typePath(source),
+		Field: field,
+		Rule:  fmt.Sprintf(format, args...),
+	}
+	for _, e := range b.errors {
+		if *e == *err {
+			return
+		}
+	}
+	b.errors = append(b.errors, err)
+}
+
+// warn - reports something which does not prevent building the schema, once
+func (b *Builder) warn(source reflect.Type, field string, format string, args ...interface{}) {
+	if b.Warnf == nil {
+		return
+	}
+	w := (&FieldError{
+		Type:  typePath(source),
+		Field: field,
+		Rule:  fmt.Sprintf(format, args...),
+	}).Error()
+	if b.warned[w] {
+		return
+	}
+	b.warned[w] = true
+	b.Warnf("%s", w)
+}
+
+// Err - the problems found so far, or nil if there were none
+func (b *Builder) Err() error {
+	if len(b.errors) == 0 {
+		return nil
+	}
+	return &SchemaError{Errors: b.errors}
+}
+
+func typePath(source reflect.Type) string {
+	if source == nil {
+		return ""
+	}
+	for source.Kind() == reflect.Ptr {
+		source = source.Elem()
+	}
+	if source.Name() == "" || source.PkgPath() == "" {
+		return source.String()
+	}
+	return source.PkgPath() + "." + source.Name()
+}
@@ -1,6 +1,10 @@
 package builder
 
-import "reflect"
+import (
+	"reflect"
+
+	"github.com/cipriantarta/gogql/pkg/tags"
+)
 
 func typeName(source reflect.Type) string {
 	if source.Kind() == reflect.Ptr {
@@ -16,3 +20,25 @@ func isSequence(source reflect.Value) bool {
 func isPtr(source reflect.Value) bool {
 	return source.Kind() == reflect.Ptr
 }
+
+// embedded - the struct type of an embedded struct field, or nil
+func embedded(field reflect.StructField) reflect.Type {
+	if !field.Anonymous {
+		return nil
+	}
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// hasOption - whether a graphql tag holds the option. Invalid tags are
+// reported when the field is built
+func hasOption(tag string, option string) bool {
+	options, _ := tags.Parse(tag, fieldOptions)
+	return options.Has(option)
+}
@@ -0,0 +1,236 @@
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// IntMapping - the scalar a Go integer kind maps to
+type IntMapping int
+
+const (
+	// MapToInt - graphql.Int, a 32-bit signed integer
+	MapToInt IntMapping = iota
+	// MapToLong - Long, a 64-bit signed integer serialized as a number
+	MapToLong
+	// MapToLongString - Long, a 64-bit signed integer serialized as a string,
+	// for clients which read JSON numbers as doubles
+	MapToLongString
+)
+
+// Long - a 64-bit signed integer serialized as a JSON number
+var Long = newLong(false)
+
+// LongString - a 64-bit signed integer serialized as a string, named Long too
+var LongString = newLong(true)
+
+// wideKinds - the integer kinds which may not fit graphql.Int
+var wideKinds = map[reflect.Kind]bool{
+	reflect.Int:    true,
+	reflect.Int64:  true,
+	reflect.Uint:   true,
+	reflect.Uint32: true,
+	reflect.Uint64: true,
+}
+
+func newLong(asString bool) *graphql.Scalar {
+	return graphql.NewScalar(graphql.ScalarConfig{
+		Name:        "Long",
+		Description: "A 64-bit signed integer",
+		Serialize: func(value interface{}) interface{} {
+			v := reflect.ValueOf(value)
+			for v.Kind() == reflect.Ptr && !v.IsNil() {
+				v = v.Elem()
+			}
+			var i int64
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				i = v.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				if v.Uint() > math.MaxInt64 {
+					return nil
+				}
+				i = int64(v.Uint())
+			default:
+				return nil
+			}
+			if asString {
+				return strconv.FormatInt(i, 10)
+			}
+			return i
+		},
+		ParseValue: func(value interface{}) interface{} {
+			switch value := value.(type) {
+			case int:
+				return int64(value)
+			case int64:
+				return value
+			case float64:
+				if value != math.Trunc(value) {
+					return nil
+				}
+				if math.Abs(value) > 1<<53 {
+					return fmt.Errorf("%v is not exact as a JSON number, pass Long values above 2^53 as strings", value)
+				}
+				return int64(value)
+			case json.Number:
+				return parseLong(string(value))
+			case string:
+				return parseLong(value)
+			}
+			return nil
+		},
+		ParseLiteral: func(value ast.Value) interface{} {
+			switch value := value.(type) {
+			case *ast.IntValue:
+				return parseLong(value.Value)
+			case *ast.StringValue:
+				return parseLong(value.Value)
+			}
+			return nil
+		},
+	})
+}
+
+// parseLong - the integer, or the error an invalid argument is parsed into
+func parseLong(s string) interface{} {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		return i
+	}
+	if err.(*strconv.NumError).Err == strconv.ErrRange {
+		return fmt.Errorf("%s overflows Long", s)
+	}
+	return fmt.Errorf("invalid Long %q: expected an integer of decimal digits", s)
+}
+
+// mapInteger - the scalar of an unnamed Go integer type configured through
+// Builder.Integers, nil for the others
+func (b *Builder) mapInteger(t reflect.Type) *graphql.Scalar {
+	if _, ok := defaultScalars[t]; !ok {
+		return nil
+	}
+	mapping, ok := b.Integers[t.Kind()]
+	if !ok {
+		return nil
+	}
+	switch mapping {
+	case MapToLong:
+		return b.long(t, false)
+	case MapToLongString:
+		return b.long(t, true)
+	}
+	return graphql.Int
+}
+
+// long - Long serialized as a number or a string, which cannot be both within a schema
+func (b *Builder) long(t reflect.Type, asString bool) *graphql.Scalar {
+	for _, mapping := range b.Integers {
+		if mapping == MapToLong && asString || mapping == MapToLongString && !asString {
+			b.fail(t, "", "Long cannot be serialized both as a number and as a string")
+			break
+		}
+	}
+	if asString {
+		return LongString
+	}
+	return Long
+}
+
+// checkRange - wraps the resolver of a field holding integers which may not
+// fit its scalar, so that they fail instead of graphql-go resolving them to null
+func checkRange(source reflect.Type, gType graphql.Type, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	for source.Kind() == reflect.Ptr || source.Kind() == reflect.Slice || source.Kind() == reflect.Array {
+		source = source.Elem()
+	}
+	if !wideKinds[source.Kind()] {
+		return resolve
+	}
+	scalar, _ := graphql.GetNamed(gType).(*graphql.Scalar)
+	var min, max int64
+	switch scalar {
+	case graphql.Int:
+		min, max = math.MinInt32, math.MaxInt32
+	case Long, LongString:
+		min, max = math.MinInt64, math.MaxInt64
+	default:
+		return resolve
+	}
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		value, err := resolve(p)
+		if err != nil {
+			return value, err
+		}
+		if err := inRange(reflect.ValueOf(value), scalar.Name(), min, max); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+func inRange(v reflect.Value, scalar string, min int64, max int64) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return inRange(v.Elem(), scalar, min, max)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := inRange(v.Index(i), scalar, min, max); err != nil {
+				return err
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < min || v.Int() > max {
+			return fmt.Errorf("%d overflows %s", v.Int(), scalar)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > uint64(max) {
+			return fmt.Errorf("%d overflows %s", v.Uint(), scalar)
+		}
+	}
+	return nil
+}
+
+// overflow - fails integer arguments which do not fit the Go type they are
+// decoded into, which mapstructure would otherwise truncate
+func overflow(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	v := reflect.ValueOf(data)
+	target := reflect.New(to).Elem()
+	var fits bool
+	switch from.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch to.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fits = !target.OverflowInt(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			fits = v.Int() >= 0 && !target.OverflowUint(uint64(v.Int()))
+		default:
+			return data, nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch to.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			fits = v.Uint() <= math.MaxInt64 && !target.OverflowInt(int64(v.Uint()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			fits = !target.OverflowUint(v.Uint())
+		default:
+			return data, nil
+		}
+	default:
+		return data, nil
+	}
+	if !fits {
+		return nil, fmt.Errorf("%v overflows %s", data, to)
+	}
+	return data, nil
+}
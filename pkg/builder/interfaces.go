@@ -1,23 +1,32 @@
 package builder
 
-import "github.com/graphql-go/graphql"
+import (
+	"reflect"
 
-//IPageInfo pagination information interface
-var IPageInfo = graphql.NewInterface(graphql.InterfaceConfig{
-	Name: "IPageInfo",
-	Fields: graphql.Fields{
-		"startCursor": &graphql.Field{
-			Type: graphql.String,
-		},
-		"endCursor": &graphql.Field{
-			Type: graphql.String,
-		},
-		"hasMore": &graphql.Field{
-			Type: graphql.NewNonNull(graphql.Boolean),
+	"github.com/graphql-go/graphql"
+)
+
+//IPageInfo pagination information interface. Builders use their own copy of
+//it, since graphql-go defines the fields of a type lazily on first use
+var IPageInfo = newPageInfoInterface()
+
+func newPageInfoInterface() *graphql.Interface {
+	return graphql.NewInterface(graphql.InterfaceConfig{
+		Name: "IPageInfo",
+		Fields: graphql.Fields{
+			"startCursor": &graphql.Field{
+				Type: graphql.String,
+			},
+			"endCursor": &graphql.Field{
+				Type: graphql.String,
+			},
+			"hasMore": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+			},
 		},
-	},
-	Description: "Relay pagination",
-})
+		Description: "Relay pagination",
+	})
+}
 
 func (b *Builder) buildInterfaces() {
 	if _, ok := b.interfaces["INode"]; !ok {
@@ -25,7 +34,7 @@ func (b *Builder) buildInterfaces() {
 			Name: "INode",
 			Fields: graphql.Fields{
 				"id": &graphql.Field{
-					Type: graphql.NewNonNull(b.scalars["ID"]),
+					Type: graphql.NewNonNull(graphql.ID),
 				},
 			},
 			Description: "Relay node interface",
@@ -46,7 +55,7 @@ func (b *Builder) buildInterfaces() {
 		})
 	}
 	if _, ok := b.interfaces["IPageInfo"]; !ok {
-		b.interfaces["IPageInfo"] = IPageInfo
+		b.interfaces["IPageInfo"] = newPageInfoInterface()
 	}
 	if _, ok := b.interfaces["IConnection"]; !ok {
 		b.interfaces["IConnection"] = graphql.NewInterface(graphql.InterfaceConfig{
@@ -63,3 +72,98 @@ func (b *Builder) buildInterfaces() {
 		})
 	}
 }
+
+// Implementations - Registers the implementations of a Go interface, so that
+// fields of that interface type become the GraphQL interface of the embedded
+// struct which implements it. The interface is given as a nil pointer to it,
+// e.g. `(*Node)(nil)`
+func (b *Builder) Implementations(iface interface{}, members ...interface{}) {
+	if t, types := b.members(iface, members); t != nil {
+		b.implementations[t] = append(b.implementations[t], types...)
+	}
+}
+
+// implements - the interfaces of the promoted structs embedded with the
+// `interface` tag
+func (b *Builder) implements(source reflect.Type) []*graphql.Interface {
+	if source.Kind() == reflect.Ptr {
+		source = source.Elem()
+	}
+	result := make([]*graphql.Interface, 0)
+	if source.Kind() != reflect.Struct {
+		return result
+	}
+	for i := 0; i < source.NumField(); i++ {
+		f := source.Field(i)
+		el := b.promoted(f)
+		if el == nil {
+			continue
+		}
+		if hasOption(f.Tag.Get("graphql"), "interface") {
+			result = append(result, b.structInterface(el))
+		}
+		result = append(result, b.implements(el)...)
+	}
+	return result
+}
+
+// structInterface - the GraphQL interface for an embedded struct
+func (b *Builder) structInterface(source reflect.Type) *graphql.Interface {
+	if iface, ok := b.structIfaces[source]; ok {
+		return iface
+	}
+	name := b.typeName(source)
+	b.claim(name, source)
+	var fields graphql.Fields
+	iface := graphql.NewInterface(graphql.InterfaceConfig{
+		Name: name,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return fields
+		}),
+	})
+	b.structIfaces[source] = iface
+	fields, l := b.queryFields(reflect.New(source).Elem(), reflect.Value{})
+	b.layouts[name] = l
+	return iface
+}
+
+// mapInterface - the GraphQL interface of the embedded struct implementing a
+// Go interface. Registered implementations are built first, so that the
+// structs they embed are known
+func (b *Builder) mapInterface(source reflect.Type) *graphql.Interface {
+	if source.Kind() != reflect.Interface || source.NumMethod() == 0 {
+		return nil
+	}
+	for _, m := range b.implementations[source] {
+		el := m
+		if el.Kind() == reflect.Ptr {
+			el = el.Elem()
+		}
+		b.mapOutput(reflect.New(el).Elem(), reflect.New(el))
+	}
+	var result *graphql.Interface
+	for t, iface := range b.structIfaces {
+		if !t.Implements(source) && !reflect.PtrTo(t).Implements(source) {
+			continue
+		}
+		if result != nil {
+			b.fail(source, "", "implemented by more than one interface struct")
+			return nil
+		}
+		result = iface
+	}
+	return result
+}
+
+func isTypeOf(source reflect.Type) graphql.IsTypeOfFn {
+	if source.Kind() == reflect.Ptr {
+		source = source.Elem()
+	}
+	return func(p graphql.IsTypeOfParams) bool {
+		t := reflect.TypeOf(p.Value)
+		if t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		return t == source
+	}
+}
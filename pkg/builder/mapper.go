@@ -1,7 +1,6 @@
 package builder
 
 import (
-	"fmt"
 	"reflect"
 
 	"github.com/graphql-go/graphql"
@@ -28,7 +27,13 @@ func (b *Builder) mapOutput(source reflect.Value, parent reflect.Value) graphql.
 		return ptr
 	}
 	if source.Kind() == reflect.Struct {
-		return b.mapObject(source, parent, nil, "")
+		return b.mapObject(source, parent, nil, "", nil)
+	}
+	if union := b.mapUnion(source.Type()); union != nil {
+		return union
+	}
+	if iface := b.mapInterface(source.Type()); iface != nil {
+		return iface
 	}
 	return nil
 }
@@ -40,40 +45,85 @@ func (b *Builder) mapInput(source reflect.Value, parent reflect.Value) graphql.I
 	if ptr := b.mapPointer(source, true); ptr != nil {
 		return ptr
 	}
-	name := fmt.Sprintf("%sInput", typeName(source.Type()))
-	in, ok := b.mutationTypes[name]
-	if ok {
+	if source.Kind() != reflect.Struct {
+		return nil
+	}
+	t := source.Type()
+	for _, key := range registryKeys(t) {
+		if in, ok := b.mutationTypes[key]; ok {
+			return in
+		}
+	}
+	name := b.Naming.InputTypeName(t, b.operation)
+	key := objectKey{t: t, alias: name}
+	if in, ok := b.inputs[key]; ok {
 		return in
 	}
-
-	fields := b.InputFields(source, parent)
+	b.claim(name, t)
+	var fields graphql.InputObjectConfigFieldMap
 	o := graphql.NewInputObject(graphql.InputObjectConfig{
-		Name:   name,
-		Fields: fields,
+		Name: name,
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return fields
+		}),
 	})
-	b.mutationTypes[name] = o
+	// registered before its fields are built, for recursive input trees
+	b.inputs[key] = o
+	fields, l := b.inputFields(source, parent)
+	b.inputConfigs[o] = fields
+	b.layouts[name] = l
 	return o
 }
 
-func (b *Builder) mapObject(source reflect.Value, parent reflect.Value, interfaces []*graphql.Interface, alias string) graphql.Output {
+// mapObject - the object built from a Go struct. It is registered before its
+// fields are built, so that cyclic types refer to it instead of recursing.
+// Extra fields are added to the ones of the struct
+func (b *Builder) mapObject(source reflect.Value, parent reflect.Value, interfaces []*graphql.Interface, alias string, extra graphql.Fields) graphql.Output {
+	t := source.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	keys := registryKeys(t)
+	if alias != "" {
+		keys = []string{alias}
+	}
+	for _, key := range keys {
+		if obj, ok := b.queryTypes[key]; ok {
+			return obj
+		}
+	}
+	key := objectKey{t: t, alias: alias}
+	if obj, ok := b.objects[key]; ok {
+		return obj
+	}
+
 	name := alias
 	if name == "" {
-		name = typeName(source.Type())
-	}
-	obj, ok := b.queryTypes[name]
-	if ok {
-		return obj
+		name = b.typeName(t)
+	}
+	b.claim(name, t)
+	ifaces := interfaces
+	var fields graphql.Fields
+	obj := graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Interfaces: graphql.InterfacesThunk(func() []*graphql.Interface {
+			return ifaces
+		}),
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return fields
+		}),
+	})
+	b.objects[key] = obj
+
+	if implements := b.implements(t); len(implements) > 0 {
+		ifaces = append(append([]*graphql.Interface{}, interfaces...), implements...)
+		obj.IsTypeOf = isTypeOf(t)
 	}
-	fields, err := b.QueryFields(source, parent)
-	if err != nil {
-		panic(err)
+	fields, l := b.queryFields(source, parent)
+	for fieldName, field := range extra {
+		fields[fieldName] = field
 	}
-	obj = graphql.NewObject(graphql.ObjectConfig{
-		Name:       name,
-		Fields:     fields,
-		Interfaces: interfaces,
-	})
-	b.queryTypes[name] = obj
+	b.layouts[name] = l
 	return obj
 }
 
@@ -84,6 +134,12 @@ func (b *Builder) mapIntersection(source reflect.Value, isInput bool) Intersecti
 	if enum := b.mapEnum(source); enum != nil {
 		return enum
 	}
+	if scalar := b.mapMarshaler(source.Type()); scalar != nil {
+		return scalar
+	}
+	if scalar := b.mapFallbackScalar(source.Type()); scalar != nil {
+		return scalar
+	}
 	if sequence := b.mapSequence(source, isInput); sequence != nil {
 		return sequence
 	}
@@ -91,19 +147,49 @@ func (b *Builder) mapIntersection(source reflect.Value, isInput bool) Intersecti
 }
 
 func (b *Builder) mapScalar(source reflect.Value) *graphql.Scalar {
-	name := typeName(source.Type())
-	if s, ok := b.scalars[name]; ok {
+	t := source.Type()
+	for _, key := range registryKeys(t) {
+		if s, ok := b.scalars[key]; ok {
+			return s
+		}
+	}
+	if s := b.mapInteger(t); s != nil {
 		return s
 	}
-	return nil
+	return b.typeScalars[t]
+}
+
+// mapFallbackScalar - the scalars of maps with string keys, of byte slices
+// and of named basic types, which give way to registered scalars, enums and
+// marshalers. Named basic types map to the scalar of their kind, except for
+// string and integer types named ID, which map to graphql.ID
+func (b *Builder) mapFallbackScalar(t reflect.Type) *graphql.Scalar {
+	if t.Kind() == reflect.Map && t.Key().Kind() == reflect.String {
+		return JSON
+	}
+	if isBytes(t) {
+		if b.Base64URL {
+			return Base64URL
+		}
+		return Base64
+	}
+	basic, ok := basicTypes[t.Kind()]
+	if !ok || t == basic {
+		return nil
+	}
+	if isID(t) {
+		return graphql.ID
+	}
+	return b.mapScalar(reflect.New(basic).Elem())
 }
 
 func (b *Builder) mapEnum(source reflect.Value) *graphql.Enum {
-	name := typeName(source.Type())
-	if e, ok := b.enums[name]; ok {
-		return e
+	for _, key := range registryKeys(source.Type()) {
+		if e, ok := b.enums[key]; ok {
+			return e
+		}
 	}
-	return nil
+	return b.buildEnum(source.Type())
 }
 
 func (b *Builder) mapPointer(source reflect.Value, isInput bool) graphql.Type {
@@ -131,5 +217,38 @@ func (b *Builder) mapSequence(source reflect.Value, isInput bool) *graphql.List
 	} else {
 		inner = b.mapOutput(el.Elem(), el)
 	}
+	if inner == nil {
+		return nil
+	}
+	if !isInput && b.InferNullability && !nullable(el.Elem().Type()) {
+		inner = graphql.NewNonNull(inner)
+	}
 	return graphql.NewList(inner)
 }
+
+// objectKey - output objects are built once per Go type, or once per name for
+// the types built under a given name such as relay edges. Input objects are
+// built once per Go type and name
+type objectKey struct {
+	t     reflect.Type
+	alias string
+}
+
+// registryKeys - the names a Go type may be registered under by the user, the
+// package qualified one first
+func registryKeys(t reflect.Type) []string {
+	return []string{typePath(t), typeName(t)}
+}
+
+// claim - reserves a GraphQL type name for the Go type it is built from, so
+// that Go types of the same name from different packages are not merged
+func (b *Builder) claim(name string, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if other, ok := b.names[name]; ok && other != t {
+		b.fail(t, "", "GraphQL type %s is already built from %s. Use a package qualified naming strategy such as builder.QualifiedNaming or register one of them explicitly", name, typePath(other))
+		return
+	}
+	b.names[name] = t
+}
@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"encoding"
+	"reflect"
+
+	"github.com/cipriantarta/gogql/pkg/types"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+var (
+	marshalerType       = reflect.TypeOf((*types.Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*types.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// hasMethods - whether a type or a pointer to it implements an interface
+func hasMethods(t reflect.Type, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+// mapMarshaler - a scalar generated for a named Go type implementing
+// types.Marshaler or encoding.TextMarshaler, parsed through types.Unmarshaler
+// or encoding.TextUnmarshaler when the type implements them
+func (b *Builder) mapMarshaler(t reflect.Type) *graphql.Scalar {
+	if t.Name() == "" || t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return nil
+	}
+	if !hasMethods(t, marshalerType) && !hasMethods(t, textMarshalerType) {
+		return nil
+	}
+	name := b.typeName(t)
+	if specifiedScalars[name] {
+		b.fail(t, "", "the scalar generated from its marshaler would be named after the built-in %s. Register a scalar for it explicitly", name)
+		return nil
+	}
+	b.claim(name, t)
+
+	config := graphql.ScalarConfig{
+		Name: name,
+		Serialize: func(value interface{}) interface{} {
+			p := pointerTo(value, t)
+			if !p.IsValid() {
+				return nil
+			}
+			if m, ok := p.Interface().(types.Marshaler); ok {
+				result, err := m.MarshalGraphQL()
+				if err != nil {
+					return nil
+				}
+				return result
+			}
+			text, err := p.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return nil
+			}
+			return string(text)
+		},
+	}
+	switch {
+	case hasMethods(t, unmarshalerType):
+		config.ParseValue = func(value interface{}) interface{} {
+			return unmarshalValue(t, value)
+		}
+		config.ParseLiteral = func(value ast.Value) interface{} {
+			return unmarshalValue(t, parseJSONLiteral(value))
+		}
+	case hasMethods(t, textUnmarshalerType):
+		config.ParseValue = func(value interface{}) interface{} {
+			if _, ok := value.(string); !ok {
+				return nil
+			}
+			return unmarshalValue(t, value)
+		}
+		config.ParseLiteral = func(value ast.Value) interface{} {
+			s, ok := value.(*ast.StringValue)
+			if !ok {
+				return nil
+			}
+			return unmarshalValue(t, s.Value)
+		}
+	}
+	s := graphql.NewScalar(config)
+	b.typeScalars[t] = s
+	return s
+}
+
+// pointerTo - a pointer to the value of type t, so that methods with either
+// receiver can be called. Invalid for nil pointers and other types
+func pointerTo(value interface{}, t reflect.Type) reflect.Value {
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return reflect.Value{}
+	case v.Type() == t:
+		p := reflect.New(t)
+		p.Elem().Set(v)
+		return p
+	case v.Type() == reflect.PtrTo(t) && !v.IsNil():
+		return v
+	}
+	return reflect.Value{}
+}
+
+// unmarshalValue - a value of type t unmarshaled from a GraphQL value, or the
+// error of the unmarshaler, which the resolver then returns
+func unmarshalValue(t reflect.Type, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	p := reflect.New(t)
+	var err error
+	switch u := p.Interface().(type) {
+	case types.Unmarshaler:
+		err = u.UnmarshalGraphQL(value)
+	case encoding.TextUnmarshaler:
+		s, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		err = u.UnmarshalText([]byte(s))
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return p.Elem().Interface()
+}
+
+// unmarshal - decodes arguments into Go types implementing types.Unmarshaler
+// or encoding.TextUnmarshaler through their unmarshaler, unless they already
+// are of that type
+func unmarshal(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from == to || to.Kind() == reflect.Ptr || to.Kind() == reflect.Interface {
+		return data, nil
+	}
+	if !hasMethods(to, unmarshalerType) {
+		if _, ok := data.(string); !ok || !hasMethods(to, textUnmarshalerType) {
+			return data, nil
+		}
+	}
+	result := unmarshalValue(to, data)
+	if err, ok := result.(error); ok {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package builder
+
+import (
+	"reflect"
+
+	"github.com/cipriantarta/gogql/pkg/types"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+var fieldMaskType = reflect.TypeOf(types.FieldMask{})
+
+// fieldMask - the arguments of the resolved field a client sent, read from
+// the field's ASTs, one per selection of the field, and the variables of the
+// request, as p.Args holds neither the arguments set to null nor tells them
+// from the defaults. The variables are read as sent when the request is
+// executed by Do, which keeps those set to null
+func fieldMask(p graphql.ResolveParams) types.FieldMask {
+	mask := make(types.FieldMask)
+	sent := sentVariables(p.Context)
+	for _, field := range p.Info.FieldASTs {
+		for _, arg := range field.Arguments {
+			maskLiteral(mask, arg.Name.Value, arg.Value, p.Info.VariableValues, sent)
+		}
+	}
+	return mask
+}
+
+func maskLiteral(mask types.FieldMask, path string, value ast.Value, variables map[string]interface{}, sent map[string]interface{}) {
+	switch value := value.(type) {
+	case *ast.Variable:
+		if v, ok := sent[value.Name.Value]; ok {
+			maskValue(mask, path, v)
+			return
+		}
+		// graphql-go holds the variables left out and those set to null as
+		// nil, which its arguments treat alike as left out
+		if v := variables[value.Name.Value]; v != nil {
+			maskValue(mask, path, v)
+		}
+	case *ast.ObjectValue:
+		mask[path] = false
+		for _, f := range value.Fields {
+			maskLiteral(mask, path+"."+f.Name.Value, f.Value, variables, sent)
+		}
+	default:
+		mask[path] = false
+	}
+}
+
+func maskValue(mask types.FieldMask, path string, value interface{}) {
+	mask[path] = value == nil
+	if m, ok := value.(map[string]interface{}); ok {
+		for k, v := range m {
+			maskValue(mask, path+"."+k, v)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package builder
+
+import (
+	"path"
+	"reflect"
+
+	"github.com/cipriantarta/gogql/pkg/types"
+	"github.com/iancoleman/strcase"
+)
+
+// NamingStrategy - names the GraphQL fields, arguments, enum values and the
+// types generated from Go types. Aliases given through tags always win
+type NamingStrategy interface {
+	// TypeName - name of the object, enum, union or interface built from a Go type
+	TypeName(t reflect.Type) string
+	// FieldName - name of an output or input field built from a Go struct field
+	FieldName(goName string) string
+	// ArgumentName - name of an argument built from a field of a resolver's arguments struct
+	ArgumentName(goName string) string
+	// EnumValueName - name of an enum value, given the String() of the Go value
+	EnumValueName(value string) string
+	// InputTypeName - name of the input object built from a Go struct for the
+	// arguments of a field, given by its Go name such as CreateUser. The same
+	// struct is built once per name, so naming it after the field gives every
+	// operation an input of its own, e.g. CreateUserInput and UpdateUserInput.
+	// The structs nested in the arguments are given the same field, so the name
+	// must include t as well, e.g. CreateAddressInput for the address of a user
+	InputTypeName(t reflect.Type, field string) string
+	// NodeTypeName - name of the relay node type built from a Go struct
+	NodeTypeName(t reflect.Type) string
+	// EdgeTypeName - name of the relay edge type of a Go struct
+	EdgeTypeName(t reflect.Type) string
+	// ConnectionTypeName - name of the relay connection type of a Go struct
+	ConnectionTypeName(t reflect.Type) string
+}
+
+// DefaultNaming - lowerCamelCase fields and arguments, enum values as they are
+// and type names suffixed with Input, Node, Edge and Connection
+type DefaultNaming struct{}
+
+// FieldName - lowerCamelCase Go field name
+func (DefaultNaming) FieldName(goName string) string {
+	return strcase.ToLowerCamel(goName)
+}
+
+// ArgumentName - lowerCamelCase Go field name
+func (DefaultNaming) ArgumentName(goName string) string {
+	return strcase.ToLowerCamel(goName)
+}
+
+// EnumValueName - the value unchanged
+func (DefaultNaming) EnumValueName(value string) string {
+	return value
+}
+
+// TypeName - the Go type name
+func (DefaultNaming) TypeName(t reflect.Type) string {
+	return typeName(t)
+}
+
+// InputTypeName - e.g. UserInput
+func (DefaultNaming) InputTypeName(t reflect.Type, field string) string {
+	return typeName(t) + "Input"
+}
+
+// NodeTypeName - e.g. UserNode
+func (DefaultNaming) NodeTypeName(t reflect.Type) string {
+	return typeName(t) + "Node"
+}
+
+// EdgeTypeName - e.g. UserEdge
+func (DefaultNaming) EdgeTypeName(t reflect.Type) string {
+	return typeName(t) + "Edge"
+}
+
+// ConnectionTypeName - e.g. UserConnection
+func (DefaultNaming) ConnectionTypeName(t reflect.Type) string {
+	return typeName(t) + "Connection"
+}
+
+// SnakeCaseNaming - snake_case fields and arguments, otherwise DefaultNaming
+type SnakeCaseNaming struct {
+	DefaultNaming
+}
+
+// FieldName - snake_case Go field name
+func (SnakeCaseNaming) FieldName(goName string) string {
+	return strcase.ToSnake(goName)
+}
+
+// ArgumentName - snake_case Go field name
+func (SnakeCaseNaming) ArgumentName(goName string) string {
+	return strcase.ToSnake(goName)
+}
+
+// QualifiedNaming - DefaultNaming with type names prefixed by the name of
+// their package, e.g. BillingUser and BillingUserInput, which tells apart Go
+// types of the same name from different packages
+type QualifiedNaming struct {
+	DefaultNaming
+}
+
+// TypeName - e.g. BillingUser
+func (QualifiedNaming) TypeName(t reflect.Type) string {
+	return qualifiedName(t)
+}
+
+// InputTypeName - e.g. BillingUserInput
+func (QualifiedNaming) InputTypeName(t reflect.Type, field string) string {
+	return qualifiedName(t) + "Input"
+}
+
+// NodeTypeName - e.g. BillingUserNode
+func (QualifiedNaming) NodeTypeName(t reflect.Type) string {
+	return qualifiedName(t) + "Node"
+}
+
+// EdgeTypeName - e.g. BillingUserEdge
+func (QualifiedNaming) EdgeTypeName(t reflect.Type) string {
+	return qualifiedName(t) + "Edge"
+}
+
+// ConnectionTypeName - e.g. BillingUserConnection
+func (QualifiedNaming) ConnectionTypeName(t reflect.Type) string {
+	return qualifiedName(t) + "Connection"
+}
+
+func qualifiedName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "" {
+		return t.Name()
+	}
+	return strcase.ToCamel(path.Base(t.PkgPath())) + t.Name()
+}
+
+// naming - the naming strategy for the fields and arguments of a Go type.
+// Relay types keep the names required by the Relay specification
+func (b *Builder) naming(t reflect.Type) NamingStrategy {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case reflect.TypeOf(PageInfo{}), reflect.TypeOf(Edge{}), reflect.TypeOf(Connection{}), reflect.TypeOf(types.PageArguments{}):
+		return DefaultNaming{}
+	}
+	return b.Naming
+}
+
+// typeName - the GraphQL name of the type built from a Go type
+func (b *Builder) typeName(t reflect.Type) string {
+	return b.naming(t).TypeName(t)
+}
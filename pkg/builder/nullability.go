@@ -0,0 +1,87 @@
+package builder
+
+import (
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// modifiers - wraps the type of a field in the non-null and list modifiers its
+// tags ask for. Output fields are also non-null when nullability is inferred
+// and the Go type they resolve to cannot hold a nil value
+func (b *Builder) modifiers(owner reflect.Type, node *nodeType, t graphql.Type, output bool) graphql.Type {
+	if output && b.InferNullability && node.resolved != nil {
+		t = inferElements(t, node.resolved)
+	}
+	if node.elemRequired {
+		if list, ok := requireElements(t); ok {
+			t = list
+		} else {
+			b.fail(owner, node.name, "elem_required is only valid on lists")
+		}
+	}
+	if node.required || output && b.InferNullability && !nullable(node.resultType()) {
+		t = nonNull(t)
+	}
+	return t
+}
+
+// nullable - whether a Go value of the type may be nil
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+// inferElements - the list elements of t made non-null or nullable after the
+// Go type of their values, for fields whose resolver returns another type
+// than the one the list was built from, e.g. []*Track for a []Track field
+func inferElements(t graphql.Type, goType reflect.Type) graphql.Type {
+	for goType.Kind() == reflect.Ptr {
+		goType = goType.Elem()
+	}
+	switch t := t.(type) {
+	case *graphql.NonNull:
+		if inner := inferElements(t.OfType, goType); inner != t.OfType {
+			return graphql.NewNonNull(inner)
+		}
+	case *graphql.List:
+		if goType.Kind() != reflect.Slice && goType.Kind() != reflect.Array {
+			return t
+		}
+		inner := t.OfType
+		if el, ok := inner.(*graphql.NonNull); ok {
+			inner = el.OfType
+		}
+		inner = inferElements(inner, goType.Elem())
+		if !nullable(goType.Elem()) {
+			inner = graphql.NewNonNull(inner)
+		}
+		return graphql.NewList(inner)
+	}
+	return t
+}
+
+func nonNull(t graphql.Type) graphql.Type {
+	if _, ok := t.(*graphql.NonNull); ok {
+		return t
+	}
+	return graphql.NewNonNull(t)
+}
+
+// requireElements - [T!] for [T], keeping the list itself as it is
+func requireElements(t graphql.Type) (graphql.Type, bool) {
+	switch t := t.(type) {
+	case *graphql.NonNull:
+		list, ok := requireElements(t.OfType)
+		if !ok {
+			return t, false
+		}
+		return graphql.NewNonNull(list), true
+	case *graphql.List:
+		return graphql.NewList(nonNull(t.OfType)), true
+	}
+	return t, false
+}
@@ -0,0 +1,272 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/lexer"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// variablesKey - the context key of the variables of a request as sent
+type variablesKey struct{}
+
+// nullPrefix - the name of the variables null literals are turned into,
+// followed by their number
+const nullPrefix = "gogqlNull"
+
+// Do - executes a request like graphql.Do, keeping the arguments and input
+// fields set to null for the types.FieldMask of resolvers. graphql-go holds
+// the variables set to null and those left out alike, drops the null fields
+// of variables and does not parse null literals, so Do keeps the variables as
+// sent and turns every null literal into a variable of its type
+func Do(p graphql.Params) *graphql.Result {
+	request, nulls, errs := nullLiterals(&p.Schema, p.RequestString)
+	if len(errs) > 0 {
+		return &graphql.Result{Errors: errs}
+	}
+	variables := make(map[string]interface{}, len(p.VariableValues)+len(nulls))
+	for k, v := range p.VariableValues {
+		variables[k] = v
+	}
+	for _, name := range nulls {
+		variables[name] = nil
+	}
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	p.Context = context.WithValue(ctx, variablesKey{}, variables)
+	p.RequestString = request
+	return graphql.Do(p)
+}
+
+// sentVariables - the variables of the request as sent, when executed by Do
+func sentVariables(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	variables, _ := ctx.Value(variablesKey{}).(map[string]interface{})
+	return variables
+}
+
+// nullLiterals - the request with its null literals turned into variables,
+// declared by the operations using them, and the names of those variables.
+// The request is left as is when it has none, or when the type of one is not
+// known, for graphql-go to report its errors. Null literals in a position
+// expecting a non-null value are errors
+func nullLiterals(schema *graphql.Schema, request string) (string, []string, []gqlerrors.FormattedError) {
+	src := source.NewSource(&source.Source{Body: []byte(request), Name: "GraphQL request"})
+	tokens := nullTokens(src)
+	if len(tokens) == 0 {
+		return request, nil, nil
+	}
+	prefix := nullPrefix
+	for strings.Contains(request, prefix) {
+		prefix = "_" + prefix
+	}
+	names := make([]string, len(tokens))
+	var body strings.Builder
+	last := 0
+	for i, token := range tokens {
+		names[i] = fmt.Sprintf("%s%d", prefix, i)
+		body.WriteString(request[last:token.Start])
+		body.WriteString("$" + names[i])
+		last = token.End
+	}
+	body.WriteString(request[last:])
+	rewritten := body.String()
+	doc, err := parser.Parse(parser.ParseParams{Source: rewritten})
+	if err != nil {
+		return request, nil, nil
+	}
+
+	// the type of every null and the definitions using them and fragments
+	inputs := make(map[string]graphql.Input)
+	uses := make(map[ast.Node][]string)
+	spreads := make(map[ast.Node][]string)
+	fragments := make(map[string]ast.Node)
+	var current ast.Node
+	info := graphql.NewTypeInfo(&graphql.TypeInfoConfig{Schema: schema})
+	visitor.Visit(doc, visitor.VisitWithTypeInfo(info, &visitor.VisitorOptions{
+		Enter: func(p visitor.VisitFuncParams) (string, interface{}) {
+			switch node := p.Node.(type) {
+			case *ast.OperationDefinition:
+				current = node
+			case *ast.FragmentDefinition:
+				current = node
+				fragments[node.Name.Value] = node
+			case *ast.FragmentSpread:
+				spreads[current] = append(spreads[current], node.Name.Value)
+			case *ast.Variable:
+				if name := node.Name.Value; strings.HasPrefix(name, prefix) {
+					inputs[name] = info.InputType()
+					uses[current] = append(uses[current], name)
+				}
+			}
+			return visitor.ActionNoChange, nil
+		},
+	}), nil)
+
+	errs := make([]gqlerrors.FormattedError, 0)
+	for i, name := range names {
+		input := inputs[name]
+		if input == nil {
+			return request, nil, nil
+		}
+		if _, ok := input.(*graphql.NonNull); ok {
+			errs = append(errs, gqlerrors.FormattedError{
+				Message:   fmt.Sprintf(`Expected type "%v", found null.`, input),
+				Locations: []location.SourceLocation{location.GetLocation(src, tokens[i].Start)},
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return "", nil, errs
+	}
+
+	type insertion struct {
+		at   int
+		text string
+	}
+	insertions := make([]insertion, 0)
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		used := operationNulls(op, uses, spreads, fragments, make(map[ast.Node]bool))
+		if len(used) == 0 {
+			continue
+		}
+		declarations := make([]string, len(used))
+		for i, name := range used {
+			declarations[i] = fmt.Sprintf("$%s: %v", name, inputs[name])
+		}
+		decl := strings.Join(declarations, ", ")
+		switch {
+		case len(op.VariableDefinitions) > 0:
+			at := op.VariableDefinitions[len(op.VariableDefinitions)-1].Loc.End
+			insertions = append(insertions, insertion{at, ", " + decl})
+		case op.Name != nil:
+			insertions = append(insertions, insertion{op.Name.Loc.End, "(" + decl + ")"})
+		case rewritten[op.Loc.Start] == '{':
+			insertions = append(insertions, insertion{op.Loc.Start, "query (" + decl + ") "})
+		default:
+			insertions = append(insertions, insertion{op.Loc.Start + len(op.Operation), "(" + decl + ")"})
+		}
+	}
+	sort.Slice(insertions, func(i, j int) bool {
+		return insertions[i].at > insertions[j].at
+	})
+	for _, in := range insertions {
+		rewritten = rewritten[:in.at] + in.text + rewritten[in.at:]
+	}
+	return rewritten, names, nil
+}
+
+// operationNulls - the null variables used by a definition and the fragments
+// it spreads
+func operationNulls(def ast.Node, uses map[ast.Node][]string, spreads map[ast.Node][]string, fragments map[string]ast.Node, seen map[ast.Node]bool) []string {
+	seen[def] = true
+	result := append([]string{}, uses[def]...)
+	for _, name := range spreads[def] {
+		if fragment, ok := fragments[name]; ok && !seen[fragment] {
+			result = append(result, operationNulls(fragment, uses, spreads, fragments, seen)...)
+		}
+	}
+	return result
+}
+
+// nullTokens - the null literals of a request given as argument or input
+// field values, or in lists of them. Defaults of variables are left out, as
+// they cannot refer to other variables
+func nullTokens(src *source.Source) []lexer.Token {
+	const (
+		selection = iota
+		arguments
+		variables
+		object
+		list
+		listType
+	)
+	lex := lexer.Lex(src)
+	result := make([]lexer.Token, 0)
+	stack := make([]int, 0)
+	top := func() int {
+		if len(stack) == 0 {
+			return selection
+		}
+		return stack[len(stack)-1]
+	}
+	inVariables := func() bool {
+		for _, frame := range stack {
+			if frame == variables {
+				return true
+			}
+		}
+		return false
+	}
+	// expect - a value starts at the next token
+	var expect, variable bool
+	var prev, prev2 lexer.Token
+	for {
+		token, err := lex(0)
+		if err != nil || token.Kind == lexer.EOF {
+			return result
+		}
+		value := expect || top() == list
+		switch token.Kind {
+		case lexer.BRACE_L:
+			if value {
+				stack = append(stack, object)
+			} else {
+				stack = append(stack, selection)
+			}
+			expect = false
+		case lexer.BRACKET_L:
+			if value {
+				stack = append(stack, list)
+			} else {
+				stack = append(stack, listType)
+			}
+			expect = false
+		case lexer.PAREN_L:
+			if len(stack) > 0 || prev.Kind == lexer.NAME && prev2.Kind == lexer.AT {
+				stack = append(stack, arguments)
+			} else {
+				stack = append(stack, variables)
+			}
+			expect = false
+		case lexer.BRACE_R, lexer.BRACKET_R, lexer.PAREN_R:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			expect = false
+		case lexer.COLON:
+			expect = top() == arguments || top() == object
+		case lexer.EQUALS:
+			expect = top() == variables
+		case lexer.DOLLAR:
+			variable = value
+		case lexer.NAME:
+			if variable {
+				variable = false
+			} else if value && token.Value == "null" && !inVariables() {
+				result = append(result, token)
+			}
+			expect = false
+		default:
+			expect = false
+		}
+		prev2, prev = prev, token
+	}
+}
@@ -1,30 +1,242 @@
 package builder
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
 )
 
-var scalars = map[string]*graphql.Scalar{
-	"ID":                               graphql.ID,
-	reflect.TypeOf(bool(false)).Name(): graphql.Boolean,
-	reflect.TypeOf(int(0)).Name():      graphql.Int,
-	reflect.TypeOf(int8(0)).Name():     graphql.Int,
-	reflect.TypeOf(int16(0)).Name():    graphql.Int,
-	reflect.TypeOf(int32(0)).Name():    graphql.Int,
-	reflect.TypeOf(int64(0)).Name():    graphql.Int,
-	reflect.TypeOf(uint(0)).Name():     graphql.Int,
-	reflect.TypeOf(uint8(0)).Name():    graphql.Int,
-	reflect.TypeOf(uint16(0)).Name():   graphql.Int,
-	reflect.TypeOf(uint32(0)).Name():   graphql.Int,
-	reflect.TypeOf(uint64(0)).Name():   graphql.Int,
-	reflect.TypeOf(float32(0)).Name():  graphql.Float,
-	reflect.TypeOf(float64(0)).Name():  graphql.Float,
-	reflect.TypeOf(string("")).Name():  graphql.String,
-	reflect.TypeOf(time.Time{}).Name(): graphql.DateTime,
-	reflect.TypeOf(byte(0)).Name(): graphql.NewScalar(graphql.ScalarConfig{
+var (
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	rawMessageType     = reflect.TypeOf(json.RawMessage{})
+)
+
+// JSON - free-form values such as objects, lists, strings, numbers and booleans.
+// Fields of type interface{}, json.RawMessage and maps with string keys use it
+var JSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "A free-form JSON value",
+	Serialize: func(value interface{}) interface{} {
+		raw, ok := value.(json.RawMessage)
+		if !ok {
+			return value
+		}
+		var result interface{}
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil
+		}
+		return result
+	},
+	ParseValue: func(value interface{}) interface{} {
+		return value
+	},
+	ParseLiteral: parseJSONLiteral,
+})
+
+func parseJSONLiteral(value ast.Value) interface{} {
+	switch value := value.(type) {
+	case *ast.ObjectValue:
+		result := make(map[string]interface{}, len(value.Fields))
+		for _, f := range value.Fields {
+			v := parseJSONLiteral(f.Value)
+			if v == nil {
+				return nil
+			}
+			result[f.Name.Value] = v
+		}
+		return result
+	case *ast.ListValue:
+		result := make([]interface{}, 0, len(value.Values))
+		for _, item := range value.Values {
+			v := parseJSONLiteral(item)
+			if v == nil {
+				return nil
+			}
+			result = append(result, v)
+		}
+		return result
+	case *ast.IntValue:
+		if i, err := strconv.Atoi(value.Value); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(value.Value, 64); err == nil {
+			return f
+		}
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(value.Value, 64); err == nil {
+			return f
+		}
+	case *ast.StringValue:
+		return value.Value
+	case *ast.BooleanValue:
+		return value.Value
+	case *ast.EnumValue:
+		return value.Value
+	}
+	return nil
+}
+
+// Base64 - byte slices as standard base64 with padding. Unpadded values are
+// accepted as input too
+var Base64 = newBase64("Base64", "Binary data as standard base64", base64.StdEncoding, base64.RawStdEncoding)
+
+// Base64URL - byte slices as URL and file name safe base64 with padding.
+// Unpadded values are accepted as input too
+var Base64URL = newBase64("Base64URL", "Binary data as URL safe base64", base64.URLEncoding, base64.RawURLEncoding)
+
+func newBase64(name string, description string, encoding *base64.Encoding, unpadded *base64.Encoding) *graphql.Scalar {
+	parse := func(s string) interface{} {
+		data, err := encoding.DecodeString(s)
+		if err == nil {
+			return data
+		}
+		if data, rawErr := unpadded.DecodeString(s); rawErr == nil {
+			return data
+		}
+		return fmt.Errorf("invalid %s value: %v", name, err)
+	}
+	return graphql.NewScalar(graphql.ScalarConfig{
+		Name:        name,
+		Description: description,
+		Serialize: func(value interface{}) interface{} {
+			v := reflect.ValueOf(value)
+			for v.Kind() == reflect.Ptr && !v.IsNil() {
+				v = v.Elem()
+			}
+			if !isBytes(v.Type()) || v.IsNil() {
+				return nil
+			}
+			return encoding.EncodeToString(v.Bytes())
+		},
+		ParseValue: func(value interface{}) interface{} {
+			if s, ok := value.(string); ok {
+				return parse(s)
+			}
+			return nil
+		},
+		ParseLiteral: func(value ast.Value) interface{} {
+			if s, ok := value.(*ast.StringValue); ok {
+				return parse(s.Value)
+			}
+			return nil
+		},
+	})
+}
+
+// isBytes - whether a type is []byte or a named byte slice
+func isBytes(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// basicTypes - the unnamed Go type of each basic kind, whose scalar named
+// types of that kind fall back to
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(bool(false)),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+	reflect.String:  reflect.TypeOf(string("")),
+}
+
+// isID - whether a type is a string or integer type named ID
+func isID(t reflect.Type) bool {
+	if t.Name() != "ID" {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// basicResolver - wraps the resolver of a field of a named basic type, such as
+// `type Quantity int64`, converting its values to the unnamed type of their
+// kind, which is the only one the built-in scalars of graphql-go serialize
+func basicResolver(source reflect.Type, gType graphql.Type, resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	for source.Kind() == reflect.Ptr || source.Kind() == reflect.Slice || source.Kind() == reflect.Array {
+		source = source.Elem()
+	}
+	basic, ok := basicTypes[source.Kind()]
+	if !ok || source == basic {
+		return resolve
+	}
+	if named := graphql.GetNamed(gType); named != defaultScalars[basic] && named != graphql.ID {
+		return resolve
+	}
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		value, err := resolve(p)
+		if err != nil || value == nil {
+			return value, err
+		}
+		return toBasic(reflect.ValueOf(value), basic), nil
+	}
+}
+
+func toBasic(v reflect.Value, basic reflect.Type) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toBasic(v.Elem(), basic)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			result[i] = toBasic(v.Index(i), basic)
+		}
+		return result
+	}
+	if v.Type().ConvertibleTo(basic) {
+		return v.Convert(basic).Interface()
+	}
+	return v.Interface()
+}
+
+// defaultScalars - the built-in GraphQL scalars of Go types, copied into the
+// registry of every builder by New. Named types such as `type ID int` are not
+// scalars unless registered through Builder.Scalar or Builder.TypeScalar
+var defaultScalars = map[reflect.Type]*graphql.Scalar{
+	reflect.TypeOf(bool(false)): graphql.Boolean,
+	reflect.TypeOf(int(0)):      graphql.Int,
+	reflect.TypeOf(int8(0)):     graphql.Int,
+	reflect.TypeOf(int16(0)):    graphql.Int,
+	reflect.TypeOf(int32(0)):    graphql.Int,
+	reflect.TypeOf(int64(0)):    graphql.Int,
+	reflect.TypeOf(uint(0)):     graphql.Int,
+	reflect.TypeOf(uint16(0)):   graphql.Int,
+	reflect.TypeOf(uint32(0)):   graphql.Int,
+	reflect.TypeOf(uint64(0)):   graphql.Int,
+	reflect.TypeOf(float32(0)):  graphql.Float,
+	reflect.TypeOf(float64(0)):  graphql.Float,
+	reflect.TypeOf(string("")):  graphql.String,
+	reflect.TypeOf(time.Time{}): graphql.DateTime,
+	emptyInterfaceType:          JSON,
+	rawMessageType:              JSON,
+	reflect.TypeOf(byte(0)): graphql.NewScalar(graphql.ScalarConfig{
 		Name: "Byte",
 		Serialize: func(value interface{}) interface{} {
 			if v, ok := value.(byte); ok {
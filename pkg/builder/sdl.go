@@ -0,0 +1,380 @@
+package builder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+var specifiedScalars = map[string]bool{
+	"String":  true,
+	"Int":     true,
+	"Float":   true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// layout - declaration order of the fields of a type and of their arguments,
+// along with the Go fields they were built from
+type layout struct {
+	source    reflect.Type
+	fields    []string
+	args      map[string][]string
+	goNames   map[string]string
+	resolvers map[string]bool
+	// deprecated - reasons of deprecated input fields and arguments, which
+	// graphql-go has no place for
+	deprecated    map[string]string
+	argDeprecated map[string]map[string]string
+}
+
+func newLayout(source reflect.Type) *layout {
+	return &layout{
+		source:        source,
+		args:          make(map[string][]string),
+		goNames:       make(map[string]string),
+		resolvers:     make(map[string]bool),
+		deprecated:    make(map[string]string),
+		argDeprecated: make(map[string]map[string]string),
+	}
+}
+
+func (l *layout) field(name string, node *nodeType) {
+	l.fields = append(l.fields, name)
+	l.goNames[name] = node.name
+	if len(node.resolverOrder) > 0 {
+		l.args[name] = node.resolverOrder
+	}
+	if node.resolver != nil {
+		l.resolvers[name] = true
+	}
+	if node.deprecated != "" {
+		l.deprecated[name] = node.deprecated
+	}
+	if len(node.argDeprecated) > 0 {
+		l.argDeprecated[name] = node.argDeprecated
+	}
+}
+
+// SDL - prints the last schema built by the builder in the schema definition
+// language. Fields keep the declaration order of the Go structs they were built from
+func (b *Builder) SDL() (string, error) {
+	if b.schema == nil {
+		return "", errors.New("no schema was built yet")
+	}
+	p := &printer{layouts: b.layouts, specifiedBy: b.specifiedBy}
+	return p.schema(b.schema), nil
+}
+
+// PrintSchema - prints a schema in the schema definition language. Types
+// which were not built by a Builder have no declaration order, so their
+// fields are sorted by name
+func PrintSchema(schema *graphql.Schema) string {
+	p := &printer{}
+	return p.schema(schema)
+}
+
+type printer struct {
+	layouts     map[string]*layout
+	specifiedBy map[string]string
+}
+
+func (p *printer) schema(schema *graphql.Schema) string {
+	blocks := make([]string, 0)
+	if def := p.definition(schema); def != "" {
+		blocks = append(blocks, def)
+	}
+	typeMap := schema.TypeMap()
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		if strings.HasPrefix(name, "__") || specifiedScalars[name] {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch t := typeMap[name].(type) {
+		case *graphql.Scalar:
+			blocks = append(blocks, p.scalar(t))
+		case *graphql.Object:
+			blocks = append(blocks, p.object(t))
+		case *graphql.Interface:
+			blocks = append(blocks, p.iface(t))
+		case *graphql.Union:
+			blocks = append(blocks, p.union(t))
+		case *graphql.Enum:
+			blocks = append(blocks, p.enum(t))
+		case *graphql.InputObject:
+			blocks = append(blocks, p.input(t))
+		}
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+func (p *printer) definition(schema *graphql.Schema) string {
+	query := schema.QueryType()
+	mutation := schema.MutationType()
+	subscription := schema.SubscriptionType()
+	if (query == nil || query.Name() == "Query") &&
+		(mutation == nil || mutation.Name() == "Mutation") &&
+		(subscription == nil || subscription.Name() == "Subscription") {
+		return ""
+	}
+	lines := []string{"schema {"}
+	if query != nil {
+		lines = append(lines, "  query: "+query.Name())
+	}
+	if mutation != nil {
+		lines = append(lines, "  mutation: "+mutation.Name())
+	}
+	if subscription != nil {
+		lines = append(lines, "  subscription: "+subscription.Name())
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
+func (p *printer) scalar(t *graphql.Scalar) string {
+	s := description(t.Description(), "") + "scalar " + t.Name()
+	if url, ok := p.specifiedBy[t.Name()]; ok {
+		s += fmt.Sprintf(" @specifiedBy(url: %s)", quote(url))
+	}
+	return s
+}
+
+func (p *printer) object(t *graphql.Object) string {
+	header := "type " + t.Name()
+	if ifaces := t.Interfaces(); len(ifaces) > 0 {
+		names := make([]string, len(ifaces))
+		for i, iface := range ifaces {
+			names[i] = iface.Name()
+		}
+		header += " implements " + strings.Join(names, " & ")
+	}
+	return description(t.Description(), "") + header + p.fields(t.Name(), t.Fields())
+}
+
+func (p *printer) iface(t *graphql.Interface) string {
+	return description(t.Description(), "") + "interface " + t.Name() + p.fields(t.Name(), t.Fields())
+}
+
+func (p *printer) union(t *graphql.Union) string {
+	types := t.Types()
+	names := make([]string, len(types))
+	for i, o := range types {
+		names[i] = o.Name()
+	}
+	return description(t.Description(), "") + "union " + t.Name() + " = " + strings.Join(names, " | ")
+}
+
+func (p *printer) enum(t *graphql.Enum) string {
+	values := make(map[string]*graphql.EnumValueDefinition)
+	names := make([]string, 0)
+	for _, v := range t.Values() {
+		values[v.Name] = v
+		names = append(names, v.Name)
+	}
+	lines := []string{description(t.Description(), "") + "enum " + t.Name() + " {"}
+	for _, name := range p.order(t.Name(), names) {
+		v := values[name]
+		lines = append(lines, description(v.Description, "  ")+"  "+name+deprecated(v.DeprecationReason))
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
+func (p *printer) input(t *graphql.InputObject) string {
+	fields := t.Fields()
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	lines := []string{description(t.Description(), "") + "input " + t.Name() + " {"}
+	for _, name := range p.order(t.Name(), names) {
+		f := fields[name]
+		line := "  " + name + ": " + f.Type.String()
+		if f.DefaultValue != nil {
+			line += " = " + printValue(f.DefaultValue, f.Type)
+		}
+		if l, ok := p.layouts[t.Name()]; ok {
+			line += deprecated(l.deprecated[name])
+		}
+		lines = append(lines, description(f.Description(), "  ")+line)
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
+func (p *printer) fields(typeName string, fields graphql.FieldDefinitionMap) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	lines := []string{" {"}
+	for _, name := range p.order(typeName, names) {
+		f := fields[name]
+		line := "  " + name + p.args(typeName, f) + ": " + f.Type.String() + deprecated(f.DeprecationReason)
+		lines = append(lines, description(f.Description, "  ")+line)
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
+
+func (p *printer) args(typeName string, field *graphql.FieldDefinition) string {
+	if len(field.Args) == 0 {
+		return ""
+	}
+	args := make(map[string]*graphql.Argument)
+	names := make([]string, 0, len(field.Args))
+	multiline := false
+	for _, arg := range field.Args {
+		args[arg.Name()] = arg
+		names = append(names, arg.Name())
+		if arg.Description() != "" {
+			multiline = true
+		}
+	}
+	var argOrder []string
+	var argDeprecated map[string]string
+	if l, ok := p.layouts[typeName]; ok {
+		argOrder = l.args[field.Name]
+		argDeprecated = l.argDeprecated[field.Name]
+	}
+	printed := make([]string, 0, len(names))
+	for _, name := range arrange(argOrder, names) {
+		arg := args[name]
+		s := name + ": " + arg.Type.String()
+		if arg.DefaultValue != nil {
+			s += " = " + printValue(arg.DefaultValue, arg.Type)
+		}
+		s += deprecated(argDeprecated[name])
+		if multiline {
+			s = description(arg.Description(), "    ") + "    " + s
+		}
+		printed = append(printed, s)
+	}
+	if multiline {
+		return "(\n" + strings.Join(printed, "\n") + "\n  )"
+	}
+	return "(" + strings.Join(printed, ", ") + ")"
+}
+
+func (p *printer) order(typeName string, names []string) []string {
+	var declared []string
+	if l, ok := p.layouts[typeName]; ok {
+		declared = l.fields
+	}
+	return arrange(declared, names)
+}
+
+// arrange - names in declaration order, followed by the undeclared ones sorted
+func arrange(declared []string, names []string) []string {
+	available := make(map[string]bool, len(names))
+	for _, name := range names {
+		available[name] = true
+	}
+	result := make([]string, 0, len(names))
+	for _, name := range declared {
+		if available[name] {
+			result = append(result, name)
+			delete(available, name)
+		}
+	}
+	rest := make([]string, 0, len(available))
+	for name := range available {
+		rest = append(rest, name)
+	}
+	sort.Strings(rest)
+	return append(result, rest...)
+}
+
+// description - a block string, on one line unless the text spans several
+// lines or ends with a quote, which would run into the closing quotes
+func description(d string, indent string) string {
+	if d == "" {
+		return ""
+	}
+	d = strings.Replace(d, `"""`, `\"""`, -1)
+	if !strings.Contains(d, "\n") && !strings.HasSuffix(d, `"`) {
+		return indent + `"""` + d + `"""` + "\n"
+	}
+	lines := []string{indent + `"""`}
+	for _, line := range strings.Split(d, "\n") {
+		lines = append(lines, indent+line)
+	}
+	lines = append(lines, indent+`"""`)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func deprecated(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	if reason == graphql.DefaultDeprecationReason {
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + quote(reason) + ")"
+}
+
+func quote(s string) string {
+	q, _ := json.Marshal(s)
+	return string(q)
+}
+
+func printValue(value interface{}, ttype graphql.Type) string {
+	if value == nil {
+		return "null"
+	}
+	switch t := ttype.(type) {
+	case *graphql.NonNull:
+		return printValue(value, t.OfType)
+	case *graphql.List:
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return printValue(value, t.OfType)
+		}
+		items := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = printValue(v.Index(i).Interface(), t.OfType)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case *graphql.InputObject:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return "null"
+		}
+		fields := t.Fields()
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		items := make([]string, 0, len(names))
+		for _, name := range names {
+			if f, ok := fields[name]; ok {
+				items = append(items, name+": "+printValue(m[name], f.Type))
+			}
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case *graphql.Enum:
+		if s := t.Serialize(value); s != nil {
+			return fmt.Sprint(s)
+		}
+	case *graphql.Scalar:
+		switch s := t.Serialize(value).(type) {
+		case nil:
+			return "null"
+		case string:
+			return quote(s)
+		default:
+			return fmt.Sprint(s)
+		}
+	}
+	return "null"
+}
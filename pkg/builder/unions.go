@@ -0,0 +1,83 @@
+package builder
+
+import (
+	"reflect"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Union - Registers the implementations of a Go interface, so that fields of
+// that interface type become a GraphQL union of them. The interface is given
+// as a nil pointer to it, e.g. `(*SearchResult)(nil)`
+func (b *Builder) Union(iface interface{}, members ...interface{}) {
+	if t, types := b.members(iface, members); t != nil {
+		b.unionMembers[t] = append(b.unionMembers[t], types...)
+	}
+}
+
+func (b *Builder) members(iface interface{}, members []interface{}) (reflect.Type, []reflect.Type) {
+	t := reflect.TypeOf(iface)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+		b.fail(t, "", "expected a nil pointer to a Go interface")
+		return nil, nil
+	}
+	t = t.Elem()
+	types := make([]reflect.Type, 0, len(members))
+	for _, m := range members {
+		mt := reflect.TypeOf(m)
+		if mt == nil || !mt.Implements(t) {
+			b.fail(mt, "", "does not implement %s", typePath(t))
+			continue
+		}
+		types = append(types, mt)
+	}
+	return t, types
+}
+
+func (b *Builder) mapUnion(source reflect.Type) *graphql.Union {
+	if source.Kind() != reflect.Interface {
+		return nil
+	}
+	if u, ok := b.unions[source]; ok {
+		return u
+	}
+	members, ok := b.unionMembers[source]
+	if !ok {
+		return nil
+	}
+	name := b.typeName(source)
+	b.claim(name, source)
+
+	types := make([]*graphql.Object, 0, len(members))
+	objects := make(map[reflect.Type]*graphql.Object)
+	for _, m := range members {
+		el := m
+		if el.Kind() == reflect.Ptr {
+			el = el.Elem()
+		}
+		obj, ok := b.mapOutput(reflect.New(el).Elem(), reflect.New(el)).(*graphql.Object)
+		if !ok {
+			b.fail(m, "", "union member of %s must be a struct", typePath(source))
+			continue
+		}
+		types = append(types, obj)
+		objects[el] = obj
+	}
+	// a member may refer back to the union, which is then built already
+	if u, ok := b.unions[source]; ok {
+		return u
+	}
+	u := graphql.NewUnion(graphql.UnionConfig{
+		Name:  name,
+		Types: types,
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			t := reflect.TypeOf(p.Value)
+			if t != nil && t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			return objects[t]
+		},
+	})
+	b.unions[source] = u
+	return u
+}
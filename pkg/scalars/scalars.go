@@ -0,0 +1,297 @@
+// Package scalars - an opt-in library of scalars for common Go types, mapped
+// to them on a builder by Register
+package scalars
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cipriantarta/gogql/pkg/builder"
+	"github.com/cipriantarta/gogql/pkg/types"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// decimalPrecision - mantissa bits of the parsed decimals, enough for 76 significant digits
+const decimalPrecision = 256
+
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?$`)
+
+// ParseError - an invalid scalar value. graphql-go reports any value a scalar
+// fails to parse with a generic message, so the scalars of this package parse
+// invalid values of the right kind into a ParseError instead, which the
+// resolver of the field then returns
+type ParseError struct {
+	Scalar string
+	Value  string
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Scalar, e.Value, e.Reason)
+}
+
+var (
+	// Duration - time.Duration in the Go format, e.g. 1h30m
+	Duration = newScalar("Duration", "A duration such as 1h30m or 250ms", false, func(value interface{}) interface{} {
+		if d, ok := value.(time.Duration); ok {
+			return d.String()
+		}
+		return nil
+	}, func(s string) (interface{}, string) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, "expected a number and unit such as 1h30m, 1.5s or 250ms"
+		}
+		return d, ""
+	})
+
+	// Date - types.Date as an RFC 3339 full-date, e.g. 2024-02-29
+	Date = newScalar("Date", "A calendar date such as 2024-02-29", false, func(value interface{}) interface{} {
+		if d, ok := value.(types.Date); ok {
+			return d.String()
+		}
+		return nil
+	}, func(s string) (interface{}, string) {
+		t, reason := parseTime(s, "2006-01-02", "YYYY-MM-DD")
+		if reason != "" {
+			return nil, reason
+		}
+		return types.Date{Time: t}, ""
+	})
+
+	// LocalTime - types.LocalTime as an RFC 3339 partial-time, e.g. 13:45:00
+	LocalTime = newScalar("LocalTime", "A time of day such as 13:45:00 or 13:45:00.250", false, func(value interface{}) interface{} {
+		if t, ok := value.(types.LocalTime); ok {
+			return t.String()
+		}
+		return nil
+	}, func(s string) (interface{}, string) {
+		t, reason := parseTime(s, "15:04:05", "hh:mm:ss")
+		if reason != "" {
+			return nil, reason
+		}
+		return types.LocalTime{Time: t}, ""
+	})
+
+	// URL - an absolute url.URL
+	URL = newScalar("URL", "An absolute URL such as https://example.com/docs", false, func(value interface{}) interface{} {
+		if u, ok := value.(url.URL); ok {
+			return u.String()
+		}
+		return nil
+	}, func(s string) (interface{}, string) {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, err.(*url.Error).Err.Error()
+		}
+		if !u.IsAbs() {
+			return nil, "expected an absolute URL with a scheme"
+		}
+		return *u, ""
+	})
+
+	// IP - net.IP as an IPv4 or IPv6 address
+	IP = newScalar("IP", "An IPv4 or IPv6 address such as 192.0.2.1 or 2001:db8::1", false, func(value interface{}) interface{} {
+		if ip, ok := value.(net.IP); ok && ip != nil {
+			return ip.String()
+		}
+		return nil
+	}, func(s string) (interface{}, string) {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, "expected an IPv4 address such as 192.0.2.1 or an IPv6 address such as 2001:db8::1"
+		}
+		return ip, ""
+	})
+
+	// BigInt - big.Int as a string of decimal digits, accepting integer literals too
+	BigInt = newScalar("BigInt", "An integer of arbitrary size, as a string of decimal digits", true, func(value interface{}) interface{} {
+		if i, ok := value.(big.Int); ok {
+			return i.String()
+		}
+		return nil
+	}, func(s string) (interface{}, string) {
+		i, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, "expected an integer of decimal digits"
+		}
+		return *i, ""
+	})
+
+	// Decimal - big.Float as a decimal string, accepting number literals too
+	Decimal = newScalar("Decimal", "A decimal number of arbitrary size, as a string such as 12.50", true, func(value interface{}) interface{} {
+		if f, ok := value.(big.Float); ok && !f.IsInf() {
+			return f.Text('f', -1)
+		}
+		return nil
+	}, func(s string) (interface{}, string) {
+		if !decimalPattern.MatchString(s) {
+			return nil, "expected a decimal number such as 12.50 or 1.2e3"
+		}
+		f, _, err := big.ParseFloat(s, 10, decimalPrecision, big.ToNearestEven)
+		if err != nil {
+			return nil, "out of range"
+		}
+		return *f, ""
+	})
+
+	// Email - types.Email as a bare address, e.g. ann@example.com
+	Email = newScalar("Email", "An email address such as ann@example.com", false, func(value interface{}) interface{} {
+		if e, ok := value.(types.Email); ok {
+			return string(e)
+		}
+		return nil
+	}, func(s string) (interface{}, string) {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return nil, strings.TrimPrefix(err.Error(), "mail: ")
+		}
+		if addr.Name != "" || addr.Address != s {
+			return nil, "expected a bare address such as ann@example.com"
+		}
+		return types.Email(s), ""
+	})
+
+	// UUID - types.UUID or [16]byte in the canonical form, e.g. 123e4567-e89b-12d3-a456-426614174000
+	UUID = newScalar("UUID", "A UUID such as 123e4567-e89b-12d3-a456-426614174000", false, func(value interface{}) interface{} {
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Array || !v.Type().ConvertibleTo(uuidType) {
+			return nil
+		}
+		return types.UUID(v.Convert(uuidType).Interface().([16]byte)).String()
+	}, func(s string) (interface{}, string) {
+		if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return nil, "expected 32 hex digits grouped as 8-4-4-4-12"
+		}
+		var u types.UUID
+		digits := s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+		if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+			return nil, "expected 32 hex digits grouped as 8-4-4-4-12"
+		}
+		return u, ""
+	})
+)
+
+var uuidType = reflect.TypeOf([16]byte{})
+
+// Register - maps the Go types below to the scalars of this package and sets
+// their @specifiedBy URLs
+//
+//	time.Duration          Duration
+//	types.Date             Date
+//	types.LocalTime        LocalTime
+//	url.URL                URL
+//	net.IP                 IP
+//	big.Int                BigInt
+//	big.Float              Decimal
+//	types.Email            Email
+//	types.UUID, [16]byte   UUID
+func Register(b *builder.Builder) {
+	b.TypeScalar(time.Duration(0), Duration)
+	b.TypeScalar(types.Date{}, Date)
+	b.TypeScalar(types.LocalTime{}, LocalTime)
+	b.TypeScalar(url.URL{}, URL)
+	b.TypeScalar(net.IP{}, IP)
+	b.TypeScalar(big.Int{}, BigInt)
+	b.TypeScalar(big.Float{}, Decimal)
+	b.TypeScalar(types.Email(""), Email)
+	b.TypeScalar(types.UUID{}, UUID)
+	b.TypeScalar([16]byte{}, UUID)
+
+	b.SpecifiedBy("Duration", "https://pkg.go.dev/time#ParseDuration")
+	b.SpecifiedBy("Date", "https://www.rfc-editor.org/rfc/rfc3339#section-5.6")
+	b.SpecifiedBy("LocalTime", "https://www.rfc-editor.org/rfc/rfc3339#section-5.6")
+	b.SpecifiedBy("URL", "https://www.rfc-editor.org/rfc/rfc3986")
+	b.SpecifiedBy("IP", "https://www.rfc-editor.org/rfc/rfc4291#section-2.2")
+	b.SpecifiedBy("BigInt", "https://pkg.go.dev/math/big#Int.SetString")
+	b.SpecifiedBy("Decimal", "https://pkg.go.dev/math/big#Float.Parse")
+	b.SpecifiedBy("Email", "https://www.rfc-editor.org/rfc/rfc5322#section-3.4.1")
+	b.SpecifiedBy("UUID", "https://www.rfc-editor.org/rfc/rfc4122")
+}
+
+// newScalar - a scalar serialized as a string. Strings which do not parse
+// become a ParseError carrying the reason. Numeric scalars also accept number
+// literals and variables
+func newScalar(name string, description string, numeric bool, serialize func(interface{}) interface{}, parse func(string) (interface{}, string)) *graphql.Scalar {
+	parseString := func(s string) interface{} {
+		value, reason := parse(s)
+		if reason != "" {
+			return &ParseError{Scalar: name, Value: s, Reason: reason}
+		}
+		return value
+	}
+	return graphql.NewScalar(graphql.ScalarConfig{
+		Name:        name,
+		Description: description,
+		Serialize: func(value interface{}) interface{} {
+			return serialize(indirect(value))
+		},
+		ParseValue: func(value interface{}) interface{} {
+			switch value := value.(type) {
+			case string:
+				return parseString(value)
+			case int:
+				if numeric {
+					return parseString(strconv.Itoa(value))
+				}
+			case float64:
+				if numeric {
+					return parseString(strconv.FormatFloat(value, 'f', -1, 64))
+				}
+			}
+			return nil
+		},
+		ParseLiteral: func(value ast.Value) interface{} {
+			switch value := value.(type) {
+			case *ast.StringValue:
+				return parseString(value.Value)
+			case *ast.IntValue:
+				if numeric {
+					return parseString(value.Value)
+				}
+			case *ast.FloatValue:
+				if numeric {
+					return parseString(value.Value)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// parseTime - parses a time of the given layout, with the reason it is invalid
+func parseTime(s string, layout string, format string) (time.Time, string) {
+	t, err := time.Parse(layout, s)
+	if err == nil {
+		return t, ""
+	}
+	if e, ok := err.(*time.ParseError); ok && e.Message != "" {
+		return t, strings.TrimPrefix(e.Message, ": ")
+	}
+	return t, "expected the format " + format
+}
+
+// indirect - the value pointed to, or nil for nil pointers
+func indirect(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
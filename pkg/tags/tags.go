@@ -0,0 +1,188 @@
+package tags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind - what an option accepts
+type Kind int
+
+const (
+	// Flag - an option without a value, e.g. `required`
+	Flag Kind = iota
+	// Value - an option with a value, e.g. `alias=name`. Values holding commas
+	// must be quoted: `description="Lists, sorted"` or `description='Lists, sorted'`
+	Value
+	// Literal - an option with a GraphQL value, e.g. `default={tags: ["a", "b"]}`.
+	// Commas inside lists, objects and strings do not end the option
+	Literal
+)
+
+// Tag - the options of a struct tag, flags map to an empty value
+type Tag map[string]string
+
+// Has - whether the option was given
+func (t Tag) Has(key string) bool {
+	_, ok := t[key]
+	return ok
+}
+
+// Get - the value of the option, empty when not given
+func (t Tag) Get(key string) string {
+	return t[key]
+}
+
+// Parse - splits a comma separated struct tag into its options. Only the known
+// options are accepted and each of them at most once
+func Parse(tag string, known map[string]Kind) (Tag, error) {
+	result := make(Tag)
+	s := &scanner{src: tag}
+	for !s.done() {
+		key := strings.TrimSpace(s.until("=,"))
+		if key == "" {
+			return nil, fmt.Errorf("empty option at offset %d", s.pos)
+		}
+		kind, ok := known[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown option %q", key)
+		}
+		if result.Has(key) {
+			return nil, fmt.Errorf("duplicate option %q", key)
+		}
+		hasValue := s.peek() == '='
+		if hasValue {
+			s.pos++
+		}
+		if kind == Flag && hasValue {
+			return nil, fmt.Errorf("option %q takes no value", key)
+		}
+		if kind != Flag && !hasValue {
+			return nil, fmt.Errorf("option %q needs a value", key)
+		}
+
+		var value string
+		var err error
+		switch kind {
+		case Value:
+			value, err = s.value()
+		case Literal:
+			value, err = s.literal()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %v", key, err)
+		}
+		result[key] = value
+
+		if s.done() {
+			break
+		}
+		if c := s.next(); c != ',' {
+			return nil, fmt.Errorf("option %q: unexpected %q, values holding commas must be quoted", key, c)
+		}
+		if s.done() {
+			return nil, fmt.Errorf("empty option at offset %d", s.pos)
+		}
+	}
+	return result, nil
+}
+
+type scanner struct {
+	src string
+	pos int
+}
+
+func (s *scanner) done() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *scanner) peek() byte {
+	if s.done() {
+		return 0
+	}
+	return s.src[s.pos]
+}
+
+func (s *scanner) next() byte {
+	c := s.peek()
+	s.pos++
+	return c
+}
+
+// until - consumes the input up to, not including, any of the stop bytes
+func (s *scanner) until(stop string) string {
+	start := s.pos
+	for !s.done() && strings.IndexByte(stop, s.peek()) < 0 {
+		s.pos++
+	}
+	return s.src[start:s.pos]
+}
+
+// value - a plain value up to the next comma, or a quoted one
+func (s *scanner) value() (string, error) {
+	switch s.peek() {
+	case '"':
+		start := s.pos
+		if err := s.quoted('"'); err != nil {
+			return "", err
+		}
+		return strconv.Unquote(s.src[start:s.pos])
+	case '\'':
+		start := s.pos
+		if err := s.quoted('\''); err != nil {
+			return "", err
+		}
+		raw := s.src[start+1 : s.pos-1]
+		return strings.NewReplacer(`\\`, `\`, `\'`, `'`).Replace(raw), nil
+	}
+	return s.until(","), nil
+}
+
+// quoted - consumes a quoted string, including its quotes
+func (s *scanner) quoted(quote byte) error {
+	s.pos++
+	for !s.done() {
+		switch s.next() {
+		case '\\':
+			s.pos++
+		case quote:
+			return nil
+		}
+	}
+	return fmt.Errorf("unterminated %c quoted value", quote)
+}
+
+// literal - a GraphQL value up to the next comma outside of lists, objects and strings
+func (s *scanner) literal() (string, error) {
+	start := s.pos
+	closing := make([]byte, 0)
+	for !s.done() {
+		c := s.peek()
+		switch c {
+		case ',':
+			if len(closing) == 0 {
+				return strings.TrimSpace(s.src[start:s.pos]), nil
+			}
+		case '"':
+			if err := s.quoted('"'); err != nil {
+				return "", err
+			}
+			continue
+		case '[':
+			closing = append(closing, ']')
+		case '{':
+			closing = append(closing, '}')
+		case ']', '}':
+			if len(closing) == 0 || closing[len(closing)-1] != c {
+				return "", fmt.Errorf("unbalanced %q", c)
+			}
+			closing = closing[:len(closing)-1]
+		}
+		s.pos++
+	}
+	if len(closing) > 0 {
+		return "", fmt.Errorf("missing %q", closing[len(closing)-1])
+	}
+	return strings.TrimSpace(s.src[start:s.pos]), nil
+}
@@ -0,0 +1,51 @@
+package tags_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cipriantarta/gogql/pkg/tags"
+)
+
+func TestTags(t *testing.T) {
+	known := map[string]tags.Kind{
+		"required":    tags.Flag,
+		"alias":       tags.Value,
+		"description": tags.Value,
+		"default":     tags.Literal,
+	}
+	valid := map[string]tags.Tag{
+		`required,alias=id`:                    {"required": "", "alias": "id"},
+		`description="Lists, \"sorted\""`:      {"description": `Lists, "sorted"`},
+		`description='It\'s, sorted',required`: {"description": "It's, sorted", "required": ""},
+		`default={tags: ["a", "b,c"]},alias=x`: {"default": `{tags: ["a", "b,c"]}`, "alias": "x"},
+		`default="a, b"`:                       {"default": `"a, b"`},
+	}
+	for tag, expected := range valid {
+		options, err := tags.Parse(tag, known)
+		if err != nil {
+			t.Fatalf("%s: %v", tag, err)
+		}
+		if fmt.Sprint(options) != fmt.Sprint(expected) {
+			t.Fatalf("%s: expected %v, got %v", tag, expected, options)
+		}
+	}
+
+	invalid := map[string]string{
+		`requried`:                `unknown option "requried"`,
+		`required=true`:           `option "required" takes no value`,
+		`alias`:                   `option "alias" needs a value`,
+		`alias=a,alias=b`:         `duplicate option "alias"`,
+		`description="a, b`:       `option "description": unterminated " quoted value`,
+		`description="a" b`:       `option "description": unexpected ' ', values holding commas must be quoted`,
+		`default={a: [1, 2}`:      `option "default": unbalanced '}'`,
+		`required,`:               `empty option at offset 9`,
+		`description=Lists, sort`: `unknown option "sort"`,
+	}
+	for tag, expected := range invalid {
+		_, err := tags.Parse(tag, known)
+		if err == nil || err.Error() != expected {
+			t.Fatalf("%s: expected %q, got %v", tag, expected, err)
+		}
+	}
+}
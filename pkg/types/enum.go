@@ -0,0 +1,12 @@
+package types
+
+// EnumDescriber - implemented by enum values which carry a description
+type EnumDescriber interface {
+	EnumDescription() string
+}
+
+// EnumDeprecator - implemented by enum values which may be deprecated. An
+// empty reason means the value is not deprecated
+type EnumDeprecator interface {
+	EnumDeprecationReason() string
+}
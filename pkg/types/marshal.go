@@ -0,0 +1,14 @@
+package types
+
+// Marshaler - implemented by Go types which serialize themselves as a
+// GraphQL scalar, e.g. a Money type returning "12.50 EUR"
+type Marshaler interface {
+	MarshalGraphQL() (interface{}, error)
+}
+
+// Unmarshaler - implemented by Go types which parse themselves from the
+// value of a GraphQL scalar, either a variable or a literal converted to
+// strings, numbers, booleans, lists and maps
+type Unmarshaler interface {
+	UnmarshalGraphQL(value interface{}) error
+}
@@ -0,0 +1,27 @@
+package types
+
+// FieldMask - the arguments and input object fields a client sent to a
+// resolver, as dotted paths of their GraphQL names such as `input.email`.
+// Declared as the last parameter of a resolver, it tells the fields left out
+// from the ones set to null, which decode to the same zero value, e.g. in
+// patch-style update mutations.
+//
+// graphql-go does not parse null literals, holds the variables set to null
+// and those left out alike and drops the null fields of variables. Requests
+// executed by gogql.Do keep them all, so the mask lists every field sent and
+// IsNull tells which ones are null. With graphql.Do, arguments given as a
+// null variable are left out of the mask, like their value is left out of
+// the arguments, and within an object given as a variable the mask lists the
+// fields holding a value or a default
+type FieldMask map[string]bool
+
+// Has - whether the client sent the argument or field, null included
+func (m FieldMask) Has(path string) bool {
+	_, ok := m[path]
+	return ok
+}
+
+// IsNull - whether the client sent the argument or field as null
+func (m FieldMask) IsNull(path string) bool {
+	return m[path]
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Date - a calendar date without a time of day, e.g. 2024-02-29
+type Date struct {
+	time.Time
+}
+
+// String - the date in the RFC 3339 full-date format
+func (d Date) String() string {
+	return d.Format("2006-01-02")
+}
+
+// LocalTime - a time of day without a date or time zone, e.g. 13:45:00
+type LocalTime struct {
+	time.Time
+}
+
+// String - the time in the RFC 3339 partial-time format
+func (t LocalTime) String() string {
+	return t.Format("15:04:05.999999999")
+}
+
+// Email - an email address, e.g. ann@example.com
+type Email string
+
+// UUID - a universally unique identifier, see RFC 4122
+type UUID [16]byte
+
+// String - the canonical form, e.g. 123e4567-e89b-12d3-a456-426614174000
+func (u UUID) String() string {
+	s := hex.EncodeToString(u[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", s[:8], s[8:12], s[12:16], s[16:20], s[20:])
+}